@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+func abilityEffect(ability AbilityDetails) string {
+	for _, e := range ability.EffectEntries {
+		if e.Language.Name == "en" {
+			return strings.Join(strings.Fields(e.Effect), " ")
+		}
+	}
+	return ""
+}
+
+type abilityChange struct {
+	VersionGroup string `json:"version_group"`
+	Effect       string `json:"effect"`
+}
+
+// abilityChanges lists how the ability worked before each change, as given
+// by the ability's effect_changes.
+func abilityChanges(ability AbilityDetails) []abilityChange {
+	changes := []abilityChange{}
+	for _, c := range ability.EffectChanges {
+		for _, e := range c.EffectEntries {
+			if e.Language.Name == "en" {
+				changes = append(changes, abilityChange{VersionGroup: c.VersionGroup.Name, Effect: strings.Join(strings.Fields(e.Effect), " ")})
+			}
+		}
+	}
+	return changes
+}
+
+// pastAbility is one ability slot of a species that was different up to
+// and including Generation. An empty ability means the slot was empty.
+type pastAbility struct {
+	Generation string `json:"generation"`
+	Pokemon    string `json:"pokemon"`
+	Slot       int    `json:"slot"`
+	Was        string `json:"was"`
+	Now        string `json:"now"`
+}
+
+// pastAbilityChanges lists every generation in which one of p's ability
+// slots differed from today and either side of the change is abilityName.
+func pastAbilityChanges(p Pokemon, abilityName string) []pastAbility {
+	current := map[int]string{}
+	for _, a := range p.Abilities {
+		current[a.Slot] = a.Ability.Name
+	}
+	var changes []pastAbility
+	for _, past := range p.PastAbilities {
+		for _, a := range past.Abilities {
+			before, now := a.Ability.Name, current[a.Slot]
+			if before == now || (before != abilityName && now != abilityName) {
+				continue
+			}
+			changes = append(changes, pastAbility{Generation: past.Generation.Name, Pokemon: p.Name, Slot: a.Slot, Was: before, Now: now})
+		}
+	}
+	return changes
+}
+
+// generationNumber reads the roman numeral of a generation name such as
+// generation-iv, returning 0 for anything else.
+func generationNumber(name string) int {
+	values := map[rune]int{'i': 1, 'v': 5, 'x': 10}
+	numeral, ok := strings.CutPrefix(name, "generation-")
+	if !ok || numeral == "" {
+		return 0
+	}
+	n := 0
+	for i, r := range numeral {
+		v, ok := values[r]
+		if !ok {
+			return 0
+		}
+		if i+1 < len(numeral) && values[rune(numeral[i+1])] > v {
+			n -= v
+		} else {
+			n += v
+		}
+	}
+	return n
+}
+
+func commandAbility(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("ability command requires an ability name")
+	}
+	ability, err := getAbility(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(abilityNames, args[0], err)
+	}
+	changes := abilityChanges(ability)
+	history := []pastAbility{}
+	for _, p := range ability.Pokemon {
+		pokemon, err := getPokemon(p.Pokemon.Name, config.cache)
+		if err != nil {
+			return err
+		}
+		history = append(history, pastAbilityChanges(pokemon, ability.Name)...)
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return generationNumber(history[i].Generation) < generationNumber(history[j].Generation)
+	})
+	if config.structuredOutput() {
+		doc := abilityDoc{Name: ability.Name, Generation: ability.Generation.Name, Effect: abilityEffect(ability), Pokemon: []abilitySlotDoc{}, Changes: changes, History: history}
+		for _, p := range ability.Pokemon {
+			doc.Pokemon = append(doc.Pokemon, abilitySlotDoc{Name: p.Pokemon.Name, Hidden: p.IsHidden})
+		}
+		return config.emit(doc)
+	}
+
+	fmt.Println(config.t("name", ability.Name))
+	if ability.Generation.Name != "" {
+		fmt.Println(config.t("ability.introduced", ability.Generation.Name))
+	}
+	if effect := abilityEffect(ability); effect != "" {
+		fmt.Println(config.t("effect", effect))
+	}
+	fmt.Println(config.t("ability.pokemon"))
+	for _, p := range ability.Pokemon {
+		if p.IsHidden {
+			fmt.Printf("  -%s %s\n", p.Pokemon.Name, config.t("info.hidden"))
+		} else {
+			fmt.Printf("  -%s\n", p.Pokemon.Name)
+		}
+	}
+	if len(changes) > 0 {
+		fmt.Println(config.t("ability.effect_changes"))
+		for _, c := range changes {
+			fmt.Printf("  -%s\n", config.t("ability.before", c.VersionGroup, c.Effect))
+		}
+	}
+	if len(history) > 0 {
+		fmt.Println(config.t("ability.past"))
+		generation := ""
+		for _, h := range history {
+			if h.Generation != generation {
+				generation = h.Generation
+				fmt.Printf("  %s\n", config.t("ability.up_to", generation))
+			}
+			fmt.Printf("    -%s\n", config.t("ability.slot", h.Pokemon, h.Slot, abilityOrNone(config, h.Was), abilityOrNone(config, h.Now)))
+		}
+	}
+	return nil
+}
+
+func abilityOrNone(config *commandConfig, name string) string {
+	if name == "" {
+		return config.t("ability.none")
+	}
+	return name
+}
@@ -0,0 +1,217 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type userSettings struct {
+	Aliases  map[string]string `json:"aliases"`
+	Macros   map[string]string `json:"macros"`
+	Format   string            `json:"format,omitempty"`
+	Language string            `json:"language,omitempty"`
+}
+
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "config.json")
+}
+
+func loadSettings(path string) (userSettings, error) {
+	settings := userSettings{
+		Aliases: map[string]string{},
+		Macros:  map[string]string{},
+	}
+	if path == "" {
+		return settings, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return settings, err
+	}
+	if settings.Aliases == nil {
+		settings.Aliases = map[string]string{}
+	}
+	if settings.Macros == nil {
+		settings.Macros = map[string]string{}
+	}
+	return settings, nil
+}
+
+func saveSettings(config *commandConfig) error {
+	if config.configPath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(config.settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(config.configPath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(config.configPath, data, 0o644)
+}
+
+// substituteArgs replaces $1 to $9 with positional arguments and $@ with
+// all of them. Missing arguments become empty.
+func substituteArgs(body string, args []string) string {
+	body = strings.ReplaceAll(body, "$@", strings.Join(args, " "))
+	for i := 9; i >= 1; i-- {
+		value := ""
+		if i <= len(args) {
+			value = args[i-1]
+		}
+		body = strings.ReplaceAll(body, "$"+strconv.Itoa(i), value)
+	}
+	return body
+}
+
+// expandCommand resolves aliases and macros in words into the list of
+// builtin command lines to run. seen holds the aliases and macros already
+// being expanded so a definition that refers back to itself is an error
+// rather than endless recursion.
+func expandCommand(config *commandConfig, words []string, seen map[string]bool) ([][]string, error) {
+	if len(words) == 0 {
+		return nil, nil
+	}
+	name := strings.ToLower(words[0])
+	words = append([]string{name}, words[1:]...)
+	if _, builtin := commands[name]; builtin {
+		return [][]string{words}, nil
+	}
+	target, isAlias := config.settings.Aliases[name]
+	body, isMacro := config.settings.Macros[name]
+	if !isAlias && !isMacro {
+		return [][]string{words}, nil
+	}
+	if seen[name] {
+		return nil, fmt.Errorf("%s refers back to itself", name)
+	}
+	seen[name] = true
+	defer delete(seen, name)
+
+	if isAlias {
+		targetWords, err := tokenize(target)
+		if err != nil {
+			return nil, err
+		}
+		return expandCommand(config, append(targetWords, words[1:]...), seen)
+	}
+	quoted := make([]string, 0, len(words)-1)
+	for _, arg := range words[1:] {
+		quoted = append(quoted, quoteToken(arg))
+	}
+	parts, err := splitCommands(substituteArgs(body, quoted))
+	if err != nil {
+		return nil, err
+	}
+	var lines [][]string
+	for _, partWords := range parts {
+		expanded, err := expandCommand(config, partWords, seen)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, expanded...)
+	}
+	return lines, nil
+}
+
+func checkDefinitionName(name string) error {
+	if _, builtin := commands[name]; builtin {
+		return fmt.Errorf("%s is already a command", name)
+	}
+	return nil
+}
+
+func commandAlias(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		printDefinitions(config.settings.Aliases, "=")
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: alias <name> <command...>")
+	}
+	if err := checkDefinitionName(args[0]); err != nil {
+		return err
+	}
+	// As with macros, a single argument is the command as typed and several
+	// are quoted word by word, so an argument with spaces stays one word.
+	target := args[1]
+	if len(args) > 2 {
+		words := make([]string, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			words = append(words, quoteToken(arg))
+		}
+		target = strings.Join(words, " ")
+	}
+	delete(config.settings.Macros, args[0])
+	config.settings.Aliases[args[0]] = target
+	return saveSettings(config)
+}
+
+func commandMacro(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		printDefinitions(config.settings.Macros, "=")
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: macro <name> \"<command>; <command>...\"")
+	}
+	if err := checkDefinitionName(args[0]); err != nil {
+		return err
+	}
+	// A single argument is the body as typed, with its own quoting. Several
+	// arguments are kept as separate words, so quoted ; stays literal.
+	body := args[1]
+	if len(args) > 2 {
+		words := make([]string, 0, len(args)-1)
+		for _, arg := range args[1:] {
+			words = append(words, quoteToken(arg))
+		}
+		body = strings.Join(words, " ")
+	}
+	delete(config.settings.Aliases, args[0])
+	config.settings.Macros[args[0]] = body
+	return saveSettings(config)
+}
+
+func commandUnalias(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("unalias command requires an alias or macro name")
+	}
+	_, isAlias := config.settings.Aliases[args[0]]
+	_, isMacro := config.settings.Macros[args[0]]
+	if !isAlias && !isMacro {
+		return fmt.Errorf("no alias or macro named %s", args[0])
+	}
+	delete(config.settings.Aliases, args[0])
+	delete(config.settings.Macros, args[0])
+	return saveSettings(config)
+}
+
+func printDefinitions(definitions map[string]string, separator string) {
+	names := make([]string, 0, len(definitions))
+	for name := range definitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Printf("%s %s %s\n", name, separator, definitions[name])
+	}
+}
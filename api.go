@@ -0,0 +1,376 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+
+	"github.com/KindMinotaur/pokedexcli/internal/pokecache"
+	"github.com/KindMinotaur/pokedexcli/internal/sprite"
+)
+
+const apiBaseURL = "https://pokeapi.co/api/v2/"
+
+type MoveDetails struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Accuracy     int    `json:"accuracy"`
+	Power        int    `json:"power"`
+	PP           int    `json:"pp"`
+	Priority     int    `json:"priority"`
+	EffectChance int    `json:"effect_chance"`
+	Type         struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"type"`
+	DamageClass struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"damage_class"`
+	Meta struct {
+		Ailment struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"ailment"`
+		AilmentChance int `json:"ailment_chance"`
+	} `json:"meta"`
+	EffectEntries []struct {
+		Effect      string `json:"effect"`
+		ShortEffect string `json:"short_effect"`
+		Language    struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"effect_entries"`
+}
+
+type PokemonSpecies struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	BaseHappiness int    `json:"base_happiness"`
+	CaptureRate   int    `json:"capture_rate"`
+	// GenderRate is the chance of a female in eighths, or -1 for a
+	// genderless species.
+	GenderRate         int `json:"gender_rate"`
+	EvolvesFromSpecies struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"evolves_from_species"`
+	EvolutionChain struct {
+		URL string `json:"url"`
+	} `json:"evolution_chain"`
+	FlavorTextEntries []struct {
+		FlavorText string `json:"flavor_text"`
+		Language   struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+		Version struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"version"`
+	} `json:"flavor_text_entries"`
+	Genera []struct {
+		Genus    string `json:"genus"`
+		Language struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"genera"`
+	Names     localizedNames `json:"names"`
+	Varieties []struct {
+		IsDefault bool          `json:"is_default"`
+		Pokemon   namedResource `json:"pokemon"`
+	} `json:"varieties"`
+}
+
+type AbilityDetails struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Generation struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"generation"`
+	EffectEntries []struct {
+		Effect      string `json:"effect"`
+		ShortEffect string `json:"short_effect"`
+		Language    struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"effect_entries"`
+	EffectChanges []struct {
+		EffectEntries []struct {
+			Effect   string `json:"effect"`
+			Language struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"language"`
+		} `json:"effect_entries"`
+		VersionGroup struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"version_group"`
+	} `json:"effect_changes"`
+	Pokemon []struct {
+		IsHidden bool `json:"is_hidden"`
+		Slot     int  `json:"slot"`
+		Pokemon  struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"pokemon"`
+	} `json:"pokemon"`
+}
+
+type ItemDetails struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Cost     int    `json:"cost"`
+	Category struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"category"`
+	EffectEntries []struct {
+		Effect      string `json:"effect"`
+		ShortEffect string `json:"short_effect"`
+		Language    struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"language"`
+	} `json:"effect_entries"`
+}
+
+type ItemCategory struct {
+	Name   string `json:"name"`
+	Pocket struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"pocket"`
+}
+
+type BerryDetails struct {
+	ID               int    `json:"id"`
+	Name             string `json:"name"`
+	GrowthTime       int    `json:"growth_time"`
+	MaxHarvest       int    `json:"max_harvest"`
+	NaturalGiftPower int    `json:"natural_gift_power"`
+	Size             int    `json:"size"`
+	Smoothness       int    `json:"smoothness"`
+	SoilDryness      int    `json:"soil_dryness"`
+	Firmness         struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"firmness"`
+	Flavors []struct {
+		Potency int `json:"potency"`
+		Flavor  struct {
+			Name string `json:"name"`
+			URL  string `json:"url"`
+		} `json:"flavor"`
+	} `json:"flavors"`
+	Item struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"item"`
+	NaturalGiftType struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"natural_gift_type"`
+}
+
+type VersionDetails struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	VersionGroup struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"version_group"`
+}
+
+type RegionDetails struct {
+	ID        int             `json:"id"`
+	Name      string          `json:"name"`
+	Names     localizedNames  `json:"names"`
+	Locations []namedResource `json:"locations"`
+}
+
+type LocationInfo struct {
+	ID     int             `json:"id"`
+	Name   string          `json:"name"`
+	Names  localizedNames  `json:"names"`
+	Region namedResource   `json:"region"`
+	Areas  []namedResource `json:"areas"`
+}
+
+// PokemonEncounter is one location area in a Pokemon's
+// location_area_encounters list.
+type PokemonEncounter struct {
+	LocationArea   namedResource `json:"location_area"`
+	VersionDetails []struct {
+		MaxChance        int `json:"max_chance"`
+		EncounterDetails []struct {
+			MinLevel        int             `json:"min_level"`
+			MaxLevel        int             `json:"max_level"`
+			Chance          int             `json:"chance"`
+			Method          namedResource   `json:"method"`
+			ConditionValues []namedResource `json:"condition_values"`
+		} `json:"encounter_details"`
+		Version namedResource `json:"version"`
+	} `json:"version_details"`
+}
+
+type PokedexDetails struct {
+	ID             int            `json:"id"`
+	Name           string         `json:"name"`
+	IsMainSeries   bool           `json:"is_main_series"`
+	Names          localizedNames `json:"names"`
+	Region         namedResource  `json:"region"`
+	PokemonEntries []pokedexEntry `json:"pokemon_entries"`
+}
+
+type pokedexEntry struct {
+	EntryNumber    int           `json:"entry_number"`
+	PokemonSpecies namedResource `json:"pokemon_species"`
+}
+
+type GenerationDetails struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Names          localizedNames  `json:"names"`
+	MainRegion     namedResource   `json:"main_region"`
+	PokemonSpecies []namedResource `json:"pokemon_species"`
+}
+
+type notFoundError struct {
+	url string
+}
+
+func (e *notFoundError) Error() string {
+	return fmt.Sprintf("nothing found at %s", e.url)
+}
+
+// fetchResource decodes the JSON document at url into v, going through the
+// cache first and only caching successful responses.
+func fetchResource(url string, cache *pokecache.Cache, v any) error {
+	if data, ok := cache.Get(url); ok {
+		return json.Unmarshal(data, v)
+	}
+	body, err := fetchBody(url)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return err
+	}
+	cache.Add(url, body)
+	return nil
+}
+
+// fetchImage fetches and decodes the image at url through the cache.
+func fetchImage(url string, cache *pokecache.Cache) (image.Image, error) {
+	if data, ok := cache.Get(url); ok {
+		return sprite.Decode(data)
+	}
+	body, err := fetchBody(url)
+	if err != nil {
+		return nil, err
+	}
+	img, err := sprite.Decode(body)
+	if err != nil {
+		return nil, err
+	}
+	cache.Add(url, body)
+	return img, nil
+}
+
+func fetchBody(url string) ([]byte, error) {
+	res, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(res.Body)
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return nil, &notFoundError{url: url}
+	}
+	if res.StatusCode > 299 {
+		return nil, fmt.Errorf("Response failed with status code: %d and body: %s", res.StatusCode, body)
+	}
+	return body, err
+}
+
+func getVersion(versionName string, cache *pokecache.Cache) (VersionDetails, error) {
+	var version VersionDetails
+	err := fetchResource(apiBaseURL+"version/"+versionName, cache, &version)
+	return version, err
+}
+
+func getRegion(regionName string, cache *pokecache.Cache) (RegionDetails, error) {
+	var region RegionDetails
+	err := fetchResource(apiBaseURL+"region/"+regionName, cache, &region)
+	return region, err
+}
+
+func getLocation(locationName string, cache *pokecache.Cache) (LocationInfo, error) {
+	var location LocationInfo
+	err := fetchResource(apiBaseURL+"location/"+locationName, cache, &location)
+	return location, err
+}
+
+func getPokedex(pokedexName string, cache *pokecache.Cache) (PokedexDetails, error) {
+	var dex PokedexDetails
+	err := fetchResource(apiBaseURL+"pokedex/"+pokedexName, cache, &dex)
+	return dex, err
+}
+
+func getGeneration(generationName string, cache *pokecache.Cache) (GenerationDetails, error) {
+	var generation GenerationDetails
+	err := fetchResource(apiBaseURL+"generation/"+generationName, cache, &generation)
+	return generation, err
+}
+
+func getPokemonEncounters(p Pokemon, cache *pokecache.Cache) ([]PokemonEncounter, error) {
+	var encounters []PokemonEncounter
+	err := fetchResource(p.LocationAreaEncounters, cache, &encounters)
+	return encounters, err
+}
+
+func getMove(moveName string, cache *pokecache.Cache) (MoveDetails, error) {
+	var move MoveDetails
+	err := fetchResource(apiBaseURL+"move/"+moveName, cache, &move)
+	return move, err
+}
+
+func getSpecies(speciesName string, cache *pokecache.Cache) (PokemonSpecies, error) {
+	var species PokemonSpecies
+	err := fetchResource(apiBaseURL+"pokemon-species/"+speciesName, cache, &species)
+	return species, err
+}
+
+func getAbility(abilityName string, cache *pokecache.Cache) (AbilityDetails, error) {
+	var ability AbilityDetails
+	err := fetchResource(apiBaseURL+"ability/"+abilityName, cache, &ability)
+	return ability, err
+}
+
+func getItem(itemName string, cache *pokecache.Cache) (ItemDetails, error) {
+	var item ItemDetails
+	err := fetchResource(apiBaseURL+"item/"+itemName, cache, &item)
+	return item, err
+}
+
+func getItemPocket(item ItemDetails, cache *pokecache.Cache) (string, error) {
+	var category ItemCategory
+	if err := fetchResource(item.Category.URL, cache, &category); err != nil {
+		return "", err
+	}
+	return category.Pocket.Name, nil
+}
+
+func getBerry(berryName string, cache *pokecache.Cache) (BerryDetails, error) {
+	var berry BerryDetails
+	err := fetchResource(apiBaseURL+"berry/"+berryName, cache, &berry)
+	return berry, err
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type argKind int
+
+const (
+	// argName values are lowercased, like the names the API uses.
+	argName argKind = iota
+	// argText values are kept exactly as typed.
+	argText
+	// argInt values must be whole numbers.
+	argInt
+)
+
+type argSpec struct {
+	name     string
+	kind     argKind
+	optional bool
+	// repeated takes every remaining positional argument.
+	repeated    bool
+	description string
+}
+
+type flagSpec struct {
+	name  string
+	short string
+	// value names the flag's value in usage; flags without one are switches.
+	value       string
+	kind        argKind
+	description string
+}
+
+// flagValues holds the flags given to the running command, keyed by long
+// name. Switches are stored as "true".
+type flagValues map[string]string
+
+func (f flagValues) has(name string) bool {
+	_, ok := f[name]
+	return ok
+}
+
+func (cmd cliCommand) usage() string {
+	parts := []string{cmd.name}
+	for _, a := range cmd.args {
+		word := a.name
+		if a.repeated {
+			word += "..."
+		}
+		if a.optional {
+			parts = append(parts, "["+word+"]")
+		} else {
+			parts = append(parts, "<"+word+">")
+		}
+	}
+	for _, f := range cmd.flags {
+		flag := "--" + f.name
+		if f.short != "" {
+			flag = "-" + f.short + "|" + flag
+		}
+		if f.value != "" {
+			flag += " <" + f.value + ">"
+		}
+		parts = append(parts, "["+flag+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+func normalizeArg(kind argKind, name, value string) (string, error) {
+	switch kind {
+	case argName:
+		return strings.ToLower(value), nil
+	case argInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%s must be a number, got %s", name, value)
+		}
+	}
+	return value, nil
+}
+
+func (cmd cliCommand) findFlag(token string) (flagSpec, bool) {
+	for _, f := range append(cmd.flags, outputFlag) {
+		if token == "--"+f.name || f.short != "" && token == "-"+f.short {
+			return f, true
+		}
+	}
+	return flagSpec{}, false
+}
+
+// isFlag tells flags apart from positional arguments; negative numbers and a
+// lone dash are positional.
+func isFlag(token string) bool {
+	if len(token) < 2 || token[0] != '-' {
+		return false
+	}
+	_, err := strconv.Atoi(token)
+	return err != nil
+}
+
+// parseArgs checks tokens against the command's spec and returns the
+// positional arguments, normalized by kind, and the flags. Flags may appear
+// anywhere; everything after "--" is positional.
+func (cmd cliCommand) parseArgs(tokens []string) ([]string, flagValues, error) {
+	var positional []string
+	flags := flagValues{}
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "--" {
+			positional = append(positional, tokens[i+1:]...)
+			break
+		}
+		if !isFlag(token) {
+			positional = append(positional, token)
+			continue
+		}
+		name, value, hasValue := strings.Cut(token, "=")
+		f, ok := cmd.findFlag(name)
+		if !ok {
+			return nil, nil, fmt.Errorf("unknown flag %s (usage: %s)", name, cmd.usage())
+		}
+		if f.value == "" {
+			if hasValue {
+				return nil, nil, fmt.Errorf("--%s does not take a value", f.name)
+			}
+			flags[f.name] = "true"
+			continue
+		}
+		if !hasValue {
+			if i+1 >= len(tokens) {
+				return nil, nil, fmt.Errorf("--%s requires a %s", f.name, f.value)
+			}
+			i++
+			value = tokens[i]
+		}
+		value, err := normalizeArg(f.kind, "--"+f.name, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		flags[f.name] = value
+	}
+
+	for i, a := range cmd.args {
+		if i >= len(positional) {
+			if !a.optional {
+				return nil, nil, fmt.Errorf("missing %s (usage: %s)", a.name, cmd.usage())
+			}
+			break
+		}
+		end := i + 1
+		if a.repeated {
+			end = len(positional)
+		}
+		for j := i; j < end; j++ {
+			value, err := normalizeArg(a.kind, a.name, positional[j])
+			if err != nil {
+				return nil, nil, err
+			}
+			positional[j] = value
+		}
+	}
+	if n := len(cmd.args); len(positional) > n && (n == 0 || !cmd.args[n-1].repeated) {
+		return nil, nil, fmt.Errorf("too many arguments (usage: %s)", cmd.usage())
+	}
+	return positional, flags, nil
+}
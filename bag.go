@@ -0,0 +1,386 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/battle"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+const (
+	startingMoney = 3000
+	prizePerLevel = 20
+)
+
+type bagItem struct {
+	Name     string `json:"name"`
+	Pocket   string `json:"pocket"`
+	Quantity int    `json:"quantity"`
+}
+
+var bag map[string]*bagItem
+var money int
+
+var ballMultipliers = map[string]float64{
+	"poke-ball":    1,
+	"premier-ball": 1,
+	"great-ball":   1.5,
+	"ultra-ball":   2,
+	"master-ball":  255,
+}
+
+// healAmounts lists HP restored by medicine; -1 restores all HP.
+var healAmounts = map[string]int{
+	"potion":       20,
+	"super-potion": 60,
+	"hyper-potion": 120,
+	"max-potion":   -1,
+	"full-restore": -1,
+	"fresh-water":  30,
+	"soda-pop":     50,
+	"lemonade":     70,
+	"moomoo-milk":  100,
+	"oran-berry":   10,
+}
+
+// statusCures lists the condition each item cures; "any" cures all of them.
+var statusCures = map[string]string{
+	"antidote":      string(battle.StatusPoison),
+	"burn-heal":     string(battle.StatusBurn),
+	"ice-heal":      string(battle.StatusFreeze),
+	"awakening":     string(battle.StatusSleep),
+	"paralyze-heal": string(battle.StatusParalysis),
+	"full-heal":     "any",
+	"full-restore":  "any",
+	"lum-berry":     "any",
+}
+
+// revivePercent is the share of max HP a revive item restores.
+var revivePercent = map[string]int{
+	"revive":     50,
+	"max-revive": 100,
+}
+
+func newBag() map[string]*bagItem {
+	return map[string]*bagItem{
+		"poke-ball": {Name: "poke-ball", Pocket: "pokeballs", Quantity: 5},
+		"potion":    {Name: "potion", Pocket: "medicine", Quantity: 3},
+	}
+}
+
+func addToBag(name, pocket string, quantity int) {
+	if item, ok := bag[name]; ok {
+		item.Quantity += quantity
+		return
+	}
+	bag[name] = &bagItem{Name: name, Pocket: pocket, Quantity: quantity}
+}
+
+func sortedBag() []*bagItem {
+	items := make([]*bagItem, 0, len(bag))
+	for _, item := range bag {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	return items
+}
+
+func takeFromBag(name string) {
+	item := bag[name]
+	item.Quantity--
+	if item.Quantity <= 0 {
+		delete(bag, name)
+	}
+}
+
+// takeBall uses up one ball for a catch attempt.
+func takeBall(ball string) error {
+	if _, ok := bag[ball]; !ok {
+		return fmt.Errorf("you don't have any %s left (buy %s to get more)", ball, ball)
+	}
+	takeFromBag(ball)
+	return nil
+}
+
+// itemPocket looks up which bag pocket an item belongs in, falling back to
+// misc when the item data can't be fetched.
+func itemPocket(config *commandConfig, itemName string) string {
+	item, err := getItem(itemName, config.cache)
+	if err != nil {
+		return "misc"
+	}
+	pocket, err := getItemPocket(item, config.cache)
+	if err != nil {
+		return "misc"
+	}
+	return pocket
+}
+
+func commandBag(config *commandConfig, args []string) error {
+	if config.structuredOutput() {
+		return config.emit(bagDoc{Money: money, Items: append([]*bagItem{}, sortedBag()...)})
+	}
+	fmt.Println(config.t("bag.money", money))
+	if len(bag) == 0 {
+		fmt.Println(config.t("bag.empty"))
+		return nil
+	}
+	pockets := map[string][]*bagItem{}
+	for _, item := range bag {
+		pockets[item.Pocket] = append(pockets[item.Pocket], item)
+	}
+	names := make([]string, 0, len(pockets))
+	for name := range pockets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, pocket := range names {
+		items := pockets[pocket]
+		sort.Slice(items, func(i, j int) bool {
+			return items[i].Name < items[j].Name
+		})
+		fmt.Printf("%s:\n", pocket)
+		for _, item := range items {
+			fmt.Printf("  %s x%d\n", item.Name, item.Quantity)
+		}
+	}
+	return nil
+}
+
+func commandBuy(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("buy command requires an item name")
+	}
+	quantity := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("quantity must be a positive number")
+		}
+		quantity = n
+	}
+	item, err := getItem(args[0], config.cache)
+	if err != nil {
+		return err
+	}
+	if item.Cost == 0 {
+		return fmt.Errorf("%s is not for sale", item.Name)
+	}
+	total := item.Cost * quantity
+	if total > money {
+		return fmt.Errorf("you need $%d but only have $%d", total, money)
+	}
+	pocket, err := getItemPocket(item, config.cache)
+	if err != nil {
+		return err
+	}
+	money -= total
+	addToBag(item.Name, pocket, quantity)
+	fmt.Println(config.t("bag.bought", quantity, item.Name, total))
+	return nil
+}
+
+func commandGive(config *commandConfig, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("give command requires an item name and a pokemon id")
+	}
+	itemName := args[0]
+	if _, ok := bag[itemName]; !ok {
+		return fmt.Errorf("you don't have any %s", itemName)
+	}
+	id, err := parseOwnedID(args[1])
+	if err != nil {
+		return err
+	}
+	p := owned[id]
+	if p.HeldItem != "" {
+		addToBag(p.HeldItem, itemPocket(config, p.HeldItem), 1)
+		fmt.Println(config.t("bag.took", p.HeldItem, p.Species))
+	}
+	takeFromBag(itemName)
+	p.HeldItem = itemName
+	fmt.Println(config.t("bag.holding", p.Species, itemName))
+	checkEvolution(config, p)
+	return nil
+}
+
+func commandUse(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("use command requires an item name")
+	}
+	itemName := args[0]
+	if _, ok := bag[itemName]; !ok {
+		return fmt.Errorf("you don't have any %s", itemName)
+	}
+
+	if multiplier, ok := ballMultipliers[itemName]; ok {
+		if config.battle == nil {
+			return fmt.Errorf("you can only throw a %s during a battle", itemName)
+		}
+		if err := takeBall(itemName); err != nil {
+			return err
+		}
+		return catchInBattle(config, itemName, multiplier)
+	}
+
+	if len(args) < 2 {
+		return fmt.Errorf("use %s requires a pokemon id", itemName)
+	}
+	id, err := parseOwnedID(args[1])
+	if err != nil {
+		return err
+	}
+	p := owned[id]
+	species, ok := pokedex[p.Species]
+	if !ok {
+		return fmt.Errorf("no data for %s", p.Species)
+	}
+
+	_, heals := healAmounts[itemName]
+	_, cures := statusCures[itemName]
+	_, revives := revivePercent[itemName]
+	if !heals && !cures && !revives {
+		if config.battle != nil {
+			return fmt.Errorf("%s can't be used during a battle", itemName)
+		}
+		return useEvolutionItem(config, p, itemName)
+	}
+
+	state := config.battle
+	inBattle := state != nil && state.lead.ID == p.ID
+	if inBattle {
+		syncInstance(p, state.engine.Player)
+	}
+	message, used := applyMedicine(config, p, species, itemName)
+	if !used {
+		return fmt.Errorf("it won't have any effect")
+	}
+	takeFromBag(itemName)
+	fmt.Println(message)
+	if state == nil {
+		checkEvolution(config, p)
+		return nil
+	}
+	if inBattle {
+		state.engine.Player.HP = p.computedStats(species)[stats.HP] - p.Damage
+		state.engine.Player.Status = battle.ParseStatus(p.Status)
+	}
+	log, err := state.engine.Skip()
+	if err != nil {
+		return err
+	}
+	printBattleLog(log)
+	finishBattle(config)
+	return nil
+}
+
+func applyMedicine(config *commandConfig, p *ownedPokemon, species Pokemon, itemName string) (string, bool) {
+	maxHP := p.computedStats(species)[stats.HP]
+	fainted := p.Damage >= maxHP
+
+	if percent, ok := revivePercent[itemName]; ok {
+		if !fainted {
+			return "", false
+		}
+		p.Damage = maxHP - max(1, maxHP*percent/100)
+		p.Status = ""
+		return config.t("bag.revived", p.Species, maxHP-p.Damage), true
+	}
+	if fainted {
+		return "", false
+	}
+
+	healed, cured := 0, ""
+	if amount, ok := healAmounts[itemName]; ok && p.Damage > 0 {
+		if amount < 0 || amount > p.Damage {
+			amount = p.Damage
+		}
+		p.Damage -= amount
+		healed = amount
+	}
+	if cure, ok := statusCures[itemName]; ok && p.Status != "" && (cure == "any" || cure == p.Status) {
+		cured = p.Status
+		p.Status = ""
+	}
+	switch {
+	case healed > 0 && cured != "":
+		return config.t("bag.recovered_cured", p.Species, healed, cured), true
+	case healed > 0:
+		return config.t("bag.recovered", p.Species, healed), true
+	case cured != "":
+		return config.t("bag.cured", p.Species, cured), true
+	}
+	return "", false
+}
+
+func useEvolutionItem(config *commandConfig, p *ownedPokemon, itemName string) error {
+	chain, err := getEvolutionChain(p.Species, config.cache)
+	if err != nil {
+		return err
+	}
+	ctx := evolutionContext(config, p, chain)
+	ctx.UsedItem = itemName
+	target, met, ok := chain.Match(speciesName(p.Species), ctx)
+	if !ok {
+		return fmt.Errorf("it won't have any effect")
+	}
+	takeFromBag(itemName)
+	return evolveOwned(config, p, target, met)
+}
+
+func commandItem(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("item command requires an item name")
+	}
+	item, err := getItem(args[0], config.cache)
+	if err != nil {
+		return err
+	}
+	pocket, err := getItemPocket(item, config.cache)
+	if err != nil {
+		return err
+	}
+	fmt.Println(config.t("name", item.Name))
+	fmt.Println(config.t("item.category", item.Category.Name, pocket))
+	if item.Cost > 0 {
+		fmt.Println(config.t("item.cost", item.Cost))
+	}
+	for _, e := range item.EffectEntries {
+		if e.Language.Name == "en" {
+			fmt.Println(config.t("effect", strings.Join(strings.Fields(e.ShortEffect), " ")))
+			break
+		}
+	}
+	if held, ok := bag[item.Name]; ok {
+		fmt.Println(config.t("item.in_bag", held.Quantity))
+	}
+	return nil
+}
+
+func commandBerry(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("berry command requires a berry name")
+	}
+	berry, err := getBerry(args[0], config.cache)
+	if err != nil {
+		return err
+	}
+	fmt.Println(config.t("berry.name", berry.Name, berry.Item.Name))
+	fmt.Println(config.t("berry.firmness", berry.Firmness.Name))
+	fmt.Println(config.t("berry.size", float64(berry.Size)/10))
+	fmt.Println(config.t("berry.growth", berry.GrowthTime))
+	fmt.Println(config.t("berry.harvest", berry.MaxHarvest))
+	fmt.Println(config.t("berry.gift", berry.NaturalGiftType.Name, berry.NaturalGiftPower))
+	fmt.Println(config.t("berry.flavors"))
+	for _, f := range berry.Flavors {
+		if f.Potency > 0 {
+			fmt.Printf("  -%s: %d\n", f.Flavor.Name, f.Potency)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/KindMinotaur/pokedexcli/internal/battle"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+const maxKnownMoves = 4
+
+type battleState struct {
+	engine      *battle.Battle
+	lead        *ownedPokemon
+	wild        *ownedPokemon
+	wildSpecies Pokemon
+}
+
+func typeNames(p Pokemon) []string {
+	names := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		names = append(names, t.Type.Name)
+	}
+	return names
+}
+
+// knownMoves picks the last moves a species learns by level-up at or below
+// level, falling back to the first moves listed when it has no level-up data.
+// A versionGroup limits the level-up data to that game; empty means any.
+func knownMoves(p Pokemon, versionGroup string, level int) []string {
+	learnedAt := map[string]int{}
+	for _, m := range p.Moves {
+		for _, d := range m.VersionGroupDetails {
+			if d.MoveLearnMethod.Name != "level-up" || d.LevelLearnedAt > level {
+				continue
+			}
+			if versionGroup != "" && d.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if at, ok := learnedAt[m.Move.Name]; !ok || d.LevelLearnedAt < at {
+				learnedAt[m.Move.Name] = d.LevelLearnedAt
+			}
+		}
+	}
+	var names []string
+	for name := range learnedAt {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if learnedAt[names[i]] != learnedAt[names[j]] {
+			return learnedAt[names[i]] < learnedAt[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > maxKnownMoves {
+		names = names[len(names)-maxKnownMoves:]
+	}
+	if len(names) == 0 {
+		for _, m := range p.Moves {
+			if len(names) == maxKnownMoves {
+				break
+			}
+			names = append(names, m.Move.Name)
+		}
+	}
+	return names
+}
+
+func newCombatant(config *commandConfig, instance *ownedPokemon, species Pokemon) (*battle.Combatant, error) {
+	computed := instance.computedStats(species)
+	c := &battle.Combatant{
+		Name:   species.Name,
+		Level:  instance.Level,
+		Types:  typeNames(species),
+		Stats:  computed,
+		HP:     computed[stats.HP] - instance.Damage,
+		Status: battle.ParseStatus(instance.Status),
+	}
+	for _, name := range knownMoves(species, config.versionGroup, instance.Level) {
+		details, err := getMove(name, config.cache)
+		if err != nil {
+			return nil, err
+		}
+		move := battle.Move{
+			Name:          details.Name,
+			Type:          details.Type.Name,
+			DamageClass:   details.DamageClass.Name,
+			Power:         details.Power,
+			Accuracy:      details.Accuracy,
+			PP:            details.PP,
+			Priority:      details.Priority,
+			Ailment:       battle.ParseStatus(details.Meta.Ailment.Name),
+			AilmentChance: details.Meta.AilmentChance,
+		}
+		c.Moves = append(c.Moves, &battle.MoveSlot{Move: move, PP: move.PP - instance.PPUsed[move.Name]})
+	}
+	return c, nil
+}
+
+// leadPokemon is the first party member that hasn't fainted.
+func leadPokemon() (*ownedPokemon, bool) {
+	for _, id := range party {
+		p, ok := owned[id]
+		if !ok {
+			continue
+		}
+		species, ok := pokedex[p.Species]
+		if !ok {
+			continue
+		}
+		if p.Damage < p.computedStats(species)[stats.HP] {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func commandBattle(config *commandConfig, args []string) error {
+	if config.battle != nil {
+		printBattleStatus(config)
+		return nil
+	}
+	lead, ok := leadPokemon()
+	if !ok {
+		return fmt.Errorf("you have no Pokemon able to battle")
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	var wildName string
+	wildLevel := 5 + rng.Intn(26)
+	if len(args) > 0 {
+		wildName = args[0]
+	} else {
+		if config.area == nil {
+			return fmt.Errorf("explore an area first or name the pokemon to battle")
+		}
+		encounters := config.areaEncounters(config.area)
+		if len(encounters) == 0 {
+			return fmt.Errorf("no pokemon can be found here in %s", config.version)
+		}
+		encounter := encounters[rng.Intn(len(encounters))]
+		wildName = encounter.Name
+		wildLevel = encounter.rollLevel(rng)
+	}
+
+	wildSpecies, err := getPokemon(wildName, config.cache)
+	if err != nil {
+		return config.withSuggestions(pokemonNames, wildName, err)
+	}
+	markSeen(wildSpecies)
+	wild := rollPokemon(wildSpecies.Name, wildLevel, rng)
+	for _, item := range config.heldItems(wildSpecies) {
+		if rng.Intn(100) < item.Rarity {
+			wild.HeldItem = item.Name
+			break
+		}
+	}
+	wild.Gender = rollGender(config, wildSpecies.Name, rng)
+	leadSpecies := pokedex[lead.Species]
+
+	player, err := newCombatant(config, lead, leadSpecies)
+	if err != nil {
+		return err
+	}
+	opponent, err := newCombatant(config, wild, wildSpecies)
+	if err != nil {
+		return err
+	}
+	chart, err := config.typeChart()
+	if err != nil {
+		return err
+	}
+
+	config.battle = &battleState{
+		engine:      battle.New(player, opponent, chart, rng),
+		lead:        lead,
+		wild:        wild,
+		wildSpecies: wildSpecies,
+	}
+	fmt.Println(config.t("battle.appeared", wild.Species, wild.Level))
+	fmt.Println(config.t("battle.go", lead.Species))
+	printBattleStatus(config)
+	return nil
+}
+
+func printBattleStatus(config *commandConfig) {
+	state := config.battle
+	for _, c := range []*battle.Combatant{state.engine.Wild, state.engine.Player} {
+		status := ""
+		if c.Status != battle.StatusNone {
+			status = fmt.Sprintf(" [%s]", c.Status)
+		}
+		fmt.Printf("%s Lv%d: %d/%d HP%s\n", c.Name, c.Level, c.HP, c.Stats[stats.HP], status)
+	}
+	fmt.Println(config.t("battle.moves"))
+	for i, m := range state.engine.Player.Moves {
+		fmt.Printf("  %d. %s (%s, %d/%d PP)\n", i+1, m.Move.Name, m.Move.Type, m.PP, m.Move.PP)
+	}
+}
+
+func commandFight(config *commandConfig, args []string) error {
+	if config.battle == nil {
+		return fmt.Errorf("you are not in a battle")
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("fight command requires a move name or slot number")
+	}
+	moveIndex := -1
+	if slot, err := strconv.Atoi(args[0]); err == nil {
+		moveIndex = slot - 1
+	} else {
+		for i, m := range config.battle.engine.Player.Moves {
+			if m.Move.Name == args[0] {
+				moveIndex = i
+			}
+		}
+		if moveIndex < 0 && len(config.battle.engine.Player.Moves) > 0 {
+			return fmt.Errorf("%s doesn't know %s", config.battle.lead.Species, args[0])
+		}
+	}
+	log, err := config.battle.engine.Turn(moveIndex)
+	if err != nil {
+		return err
+	}
+	printBattleLog(log)
+	finishBattle(config)
+	return nil
+}
+
+func commandRun(config *commandConfig, args []string) error {
+	if config.battle == nil {
+		return fmt.Errorf("you are not in a battle")
+	}
+	log, err := config.battle.engine.Run()
+	if err != nil {
+		return err
+	}
+	printBattleLog(log)
+	finishBattle(config)
+	return nil
+}
+
+func printBattleLog(log []string) {
+	for _, line := range log {
+		fmt.Println(line)
+	}
+}
+
+// finishBattle copies the lead's battle state back onto the owned instance
+// once the battle is over and hands out experience and EVs for a win.
+func finishBattle(config *commandConfig) {
+	state := config.battle
+	engine := state.engine
+	if engine.Outcome == battle.Ongoing {
+		return
+	}
+	syncInstance(state.lead, engine.Player)
+	config.battle = nil
+	if engine.Outcome == battle.PlayerWon {
+		state.lead.EVs = stats.AddEVs(state.lead.EVs, effortYield(state.wildSpecies))
+		prize := state.wild.Level * prizePerLevel
+		money += prize
+		fmt.Println(config.t("battle.prize", prize))
+		gained := state.wildSpecies.BaseExperience * state.wild.Level / 7
+		fmt.Println(config.t("battle.exp", state.lead.Species, gained))
+		if state.lead.gainExp(gained) {
+			fmt.Println(config.t("battle.level_up", state.lead.Species, state.lead.Level))
+			checkEvolution(config, state.lead)
+		}
+	}
+}
+
+func syncInstance(instance *ownedPokemon, c *battle.Combatant) {
+	instance.Damage = c.Stats[stats.HP] - c.HP
+	instance.Status = string(c.Status)
+	for _, m := range c.Moves {
+		if used := m.Move.PP - m.PP; used > 0 {
+			if instance.PPUsed == nil {
+				instance.PPUsed = map[string]int{}
+			}
+			instance.PPUsed[m.Move.Name] = used
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+type ownedPokemon struct {
+	ID         int            `json:"id"`
+	Species    string         `json:"species"`
+	Level      int            `json:"level"`
+	Exp        int            `json:"exp"`
+	Friendship int            `json:"friendship"`
+	Nature     string         `json:"nature"`
+	IVs        stats.Spread   `json:"ivs"`
+	EVs        stats.Spread   `json:"evs"`
+	Damage     int            `json:"damage"`
+	Status     string         `json:"status"`
+	PPUsed     map[string]int `json:"pp_used"`
+	HeldItem   string         `json:"held_item"`
+	// Gender is female, male or genderless; it is empty for Pokemon caught
+	// before genders were tracked.
+	Gender string `json:"gender"`
+}
+
+const (
+	baseFriendship    = 70
+	maxFriendship     = 255
+	levelUpFriendship = 5
+)
+
+var owned map[int]*ownedPokemon
+var nextOwnedID = 1
+
+func newOwnedPokemon(species string, level int, r *rand.Rand) *ownedPokemon {
+	return registerOwned(rollPokemon(species, level, r))
+}
+
+// rollPokemon creates an instance with random IVs and nature without adding
+// it to the collection, which is how wild encounters are represented.
+func rollPokemon(species string, level int, r *rand.Rand) *ownedPokemon {
+	return &ownedPokemon{
+		Species:    species,
+		Level:      level,
+		Exp:        expForLevel(level),
+		Friendship: baseFriendship,
+		Nature:     stats.RandomNature(r).Name,
+		IVs:        stats.RandomIVs(r),
+	}
+}
+
+// rollGender picks a gender from the species' gender rate, leaving it unknown
+// when the species can't be fetched.
+func rollGender(config *commandConfig, species string, r *rand.Rand) string {
+	s, err := getSpecies(speciesName(species), config.cache)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case s.GenderRate < 0:
+		return "genderless"
+	case r.Intn(8) < s.GenderRate:
+		return "female"
+	}
+	return "male"
+}
+
+// genderNumber converts a gender to the numbering evolution data uses.
+func genderNumber(gender string) int {
+	switch gender {
+	case "female":
+		return 1
+	case "male":
+		return 2
+	}
+	return 0
+}
+
+func registerOwned(p *ownedPokemon) *ownedPokemon {
+	p.ID = nextOwnedID
+	nextOwnedID++
+	owned[p.ID] = p
+	storeOwned(p)
+	return p
+}
+
+// expForLevel uses the medium-fast growth curve for every species.
+func expForLevel(level int) int {
+	return level * level * level
+}
+
+func (o *ownedPokemon) gainExp(amount int) bool {
+	o.Exp += amount
+	leveled := false
+	for o.Level < stats.MaxLevel && o.Exp >= expForLevel(o.Level+1) {
+		o.Level++
+		o.Friendship = min(maxFriendship, o.Friendship+levelUpFriendship)
+		leveled = true
+	}
+	return leveled
+}
+
+// findOwned resolves either a numeric instance id or a species name. For a
+// species name the earliest caught instance wins.
+func findOwned(arg string) (*ownedPokemon, bool) {
+	if id, err := strconv.Atoi(arg); err == nil {
+		p, ok := owned[id]
+		return p, ok
+	}
+	for _, p := range sortedOwned() {
+		if p.Species == arg {
+			return p, true
+		}
+	}
+	return nil, false
+}
+
+func sortedOwned() []*ownedPokemon {
+	list := make([]*ownedPokemon, 0, len(owned))
+	for _, p := range owned {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+func baseStats(p Pokemon) stats.Spread {
+	var base stats.Spread
+	for _, s := range p.Stats {
+		if stat, ok := stats.ParseStat(s.Stat.Name); ok {
+			base[stat] = s.BaseStat
+		}
+	}
+	return base
+}
+
+func effortYield(p Pokemon) stats.Spread {
+	var yield stats.Spread
+	for _, s := range p.Stats {
+		if stat, ok := stats.ParseStat(s.Stat.Name); ok {
+			yield[stat] = s.Effort
+		}
+	}
+	return yield
+}
+
+func (o *ownedPokemon) nature() stats.Nature {
+	n, _ := stats.NatureByName(o.Nature)
+	return n
+}
+
+func (o *ownedPokemon) computedStats(species Pokemon) stats.Spread {
+	return stats.Compute(baseStats(species), o.IVs, o.EVs, o.Level, o.nature())
+}
+
+// sortedPokedex lists the registered species in national dex order.
+func sortedPokedex() []Pokemon {
+	list := make([]Pokemon, 0, len(pokedex))
+	for _, p := range pokedex {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].ID != list[j].ID {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/lineedit"
+)
+
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func withPrefix(candidates []string, prefix string) []string {
+	var matches []string
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) && !seen[c] {
+			seen[c] = true
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func areaPokemonNames(config *commandConfig) []string {
+	if config.area == nil {
+		return nil
+	}
+	var names []string
+	for _, e := range config.area.PokemonEncounters {
+		names = append(names, e.Pokemon.Name)
+	}
+	return names
+}
+
+func ownedNames() []string {
+	var names []string
+	for _, p := range sortedOwned() {
+		names = append(names, p.Species)
+	}
+	return names
+}
+
+func ownedIDs() []string {
+	var ids []string
+	for _, p := range sortedOwned() {
+		ids = append(ids, strconv.Itoa(p.ID))
+	}
+	return ids
+}
+
+// completer completes command names and, for the first argument, whatever
+// that command expects: areas for explore, the explored area's Pokemon for
+// catch and battle, owned Pokemon for inspect, and so on.
+func completer(config *commandConfig) lineedit.Completer {
+	return func(head, word string) []string {
+		fields := strings.Fields(head)
+		if len(fields) == 0 {
+			names := commandNames()
+			for name := range config.settings.Aliases {
+				names = append(names, name)
+			}
+			for name := range config.settings.Macros {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return withPrefix(names, word)
+		}
+		name := strings.ToLower(fields[0])
+		if strings.HasPrefix(word, "-") {
+			var flags []string
+			for _, f := range commands[name].flags {
+				flags = append(flags, "--"+f.name)
+			}
+			return withPrefix(flags, word)
+		}
+		if len(fields) > 1 {
+			return nil
+		}
+		var candidates []string
+		switch name {
+		case "explore":
+			candidates, _ = config.resourceNames(locationAreaNames)
+		case "catch", "battle":
+			candidates = areaPokemonNames(config)
+		case "inspect":
+			candidates = append(ownedNames(), ownedIDs()...)
+		case "evolve", "deposit", "withdraw":
+			candidates = ownedIDs()
+		case "locations":
+			candidates, _ = config.resourceNames(regionNames)
+		case "dex":
+			candidates, _ = config.resourceNames(pokedexNames)
+		case "areas":
+			candidates, _ = config.resourceNames(locationNames)
+		case "info", "moves", "weak", "evolutions", "where", "goto":
+			candidates, _ = config.resourceNames(pokemonNames)
+		case "move":
+			candidates, _ = config.resourceNames(moveNames)
+		case "use", "give":
+			for _, item := range sortedBag() {
+				candidates = append(candidates, item.Name)
+			}
+		case "help":
+			candidates = commandNames()
+		}
+		return withPrefix(candidates, word)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/KindMinotaur/pokedexcli/internal/render"
+)
+
+// seen holds the species met in the wild, whether or not they were caught.
+var seen map[string]bool
+
+func markSeen(p Pokemon) {
+	if p.Species.Name != "" {
+		seen[p.Species.Name] = true
+		return
+	}
+	seen[p.Name] = true
+}
+
+func sortedSeen() []string {
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func caughtSpecies() map[string]bool {
+	caught := map[string]bool{}
+	for name := range pokedex {
+		caught[speciesName(name)] = true
+	}
+	return caught
+}
+
+type dexEntryDoc struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Seen   bool   `json:"seen"`
+}
+
+type dexDoc struct {
+	Pokedex string        `json:"pokedex"`
+	Region  string        `json:"region"`
+	Total   int           `json:"total"`
+	Seen    int           `json:"seen"`
+	Caught  int           `json:"caught"`
+	Percent float64       `json:"percent"`
+	Missing []dexEntryDoc `json:"missing"`
+}
+
+// dexProgress compares a regional Pokedex with the collection. Caught
+// species count as seen, and missing entries stay in dex-number order.
+func dexProgress(dex PokedexDetails) dexDoc {
+	caught := caughtSpecies()
+	doc := dexDoc{Pokedex: dex.Name, Region: dex.Region.Name, Total: len(dex.PokemonEntries), Missing: []dexEntryDoc{}}
+	entries := dex.PokemonEntries
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].EntryNumber < entries[j].EntryNumber
+	})
+	for _, e := range entries {
+		name := e.PokemonSpecies.Name
+		switch {
+		case caught[name]:
+			doc.Caught++
+			doc.Seen++
+		case seen[name]:
+			doc.Seen++
+			doc.Missing = append(doc.Missing, dexEntryDoc{Number: e.EntryNumber, Name: name, Seen: true})
+		default:
+			doc.Missing = append(doc.Missing, dexEntryDoc{Number: e.EntryNumber, Name: name})
+		}
+	}
+	if doc.Total > 0 {
+		doc.Percent = float64(doc.Caught) * 100 / float64(doc.Total)
+	}
+	return doc
+}
+
+// generationDex treats the species introduced in a generation as a Pokedex
+// numbered by national dex number, which is each species' id.
+func generationDex(generation GenerationDetails) PokedexDetails {
+	dex := PokedexDetails{ID: generation.ID, Name: generation.Name, Names: generation.Names, Region: generation.MainRegion}
+	for _, species := range generation.PokemonSpecies {
+		dex.PokemonEntries = append(dex.PokemonEntries, pokedexEntry{EntryNumber: resourceID(species.URL), PokemonSpecies: species})
+	}
+	return dex
+}
+
+func commandDex(config *commandConfig, args []string) error {
+	if number, ok := config.flags["generation"]; ok {
+		generation, err := getGeneration(number, config.cache)
+		if err != nil {
+			return config.withSuggestions(generationNames, number, err)
+		}
+		return showDex(config, generationDex(generation))
+	}
+	if len(args) == 0 {
+		names, err := config.resourceNames(pokedexNames)
+		if err != nil {
+			return err
+		}
+		if config.structuredOutput() {
+			return config.emit(struct {
+				Pokedexes []string `json:"pokedexes"`
+			}{names})
+		}
+		for _, name := range names {
+			fmt.Println(name)
+		}
+		return nil
+	}
+	dex, err := getPokedex(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(pokedexNames, args[0], err)
+	}
+	return showDex(config, dex)
+}
+
+func showDex(config *commandConfig, dex PokedexDetails) error {
+	doc := dexProgress(dex)
+	if config.structuredOutput() {
+		return config.emit(doc)
+	}
+	fmt.Printf("%s:\n", displayName(dex.Names.in(config.language()), dex.Name))
+	fmt.Println(config.t("dex.seen", doc.Seen, doc.Total))
+	fmt.Println(config.t("dex.caught", doc.Caught, doc.Total, doc.Percent))
+	if len(doc.Missing) == 0 {
+		fmt.Println(config.t("dex.complete"))
+		return nil
+	}
+	fmt.Println(config.t("dex.missing"))
+	table := render.Table{
+		Headers: []string{"#", config.t("column.pokemon"), ""},
+		Right:   []bool{true},
+	}
+	for _, e := range doc.Missing {
+		status := ""
+		if e.Seen {
+			status = config.t("dex.seen_mark")
+		}
+		table.Append(fmt.Sprintf("%03d", e.Number), config.speciesDisplayName(e.Name), status)
+	}
+	return table.Render(os.Stdout, config.style)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/KindMinotaur/pokedexcli/internal/render"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+const maxBaseStat = 255
+
+var statColumns = []string{"HP", "Atk", "Def", "SpA", "SpD", "Spe"}
+
+// printStatTable shows base stats as a table with a bar chart, adding the
+// instance's actual stats, IVs and EVs when there is one.
+func printStatTable(config *commandConfig, p Pokemon, instance *ownedPokemon) error {
+	table := render.Table{Headers: []string{config.t("column.stat"), config.t("column.base")}, Right: []bool{false, true}}
+	if instance != nil {
+		table.Headers = append(table.Headers, config.t("column.actual"), "IV", "EV")
+		table.Right = append(table.Right, true, true, true)
+	}
+	table.Headers = append(table.Headers, "")
+
+	fixed := 24
+	if instance != nil {
+		fixed += 18
+	}
+	barWidth := min(30, max(10, config.style.Width-fixed))
+
+	var computed stats.Spread
+	if instance != nil {
+		computed = instance.computedStats(p)
+	}
+	for _, stat := range p.Stats {
+		row := []string{stat.Stat.Name, strconv.Itoa(stat.BaseStat)}
+		if instance != nil {
+			if s, known := stats.ParseStat(stat.Stat.Name); known {
+				row = append(row, strconv.Itoa(computed[s]), strconv.Itoa(instance.IVs[s]), strconv.Itoa(instance.EVs[s]))
+			} else {
+				row = append(row, "", "", "")
+			}
+		}
+		row = append(row, render.Bar(stat.BaseStat, maxBaseStat, barWidth, config.style))
+		table.Append(row...)
+	}
+	return table.Render(os.Stdout, config.style)
+}
+
+var pokedexSortKeys = append([]string{"name", "type"}, pokemonNumberKeys...)
+
+// sortPokedex orders species by one of pokedexSortKeys, breaking ties by
+// dex number.
+func sortPokedex(list []Pokemon, key string, desc bool) error {
+	var less func(a, b Pokemon) bool
+	switch key {
+	case "name":
+		less = func(a, b Pokemon) bool { return a.Name < b.Name }
+	case "type":
+		less = func(a, b Pokemon) bool { return fmt.Sprint(typeNames(a)) < fmt.Sprint(typeNames(b)) }
+	default:
+		if key == "" {
+			key = "id"
+		}
+		value, ok := pokemonNumber(key)
+		if !ok {
+			return fmt.Errorf("can't sort by %s (use %v)", key, pokedexSortKeys)
+		}
+		less = func(a, b Pokemon) bool { return value(a) < value(b) }
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if desc {
+			return less(list[j], list[i])
+		}
+		return less(list[i], list[j])
+	})
+	return nil
+}
+
+func printPokedexTable(config *commandConfig, list []Pokemon) error {
+	table := render.Table{
+		Headers: append(append([]string{"#", config.t("column.name"), config.t("column.types")}, statColumns...), config.t("column.total")),
+		Right:   []bool{true, false, false, true, true, true, true, true, true, true},
+	}
+	for _, p := range list {
+		base := baseStats(p)
+		row := []string{strconv.Itoa(p.ID), config.pokemonDisplayName(p), render.Badges(typeNames(p), config.style)}
+		for _, v := range base {
+			row = append(row, strconv.Itoa(v))
+		}
+		table.Append(append(row, strconv.Itoa(base.Total()))...)
+	}
+	return table.Render(os.Stdout, config.style)
+}
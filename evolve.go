@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KindMinotaur/pokedexcli/internal/evolution"
+	"github.com/KindMinotaur/pokedexcli/internal/pokecache"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+func speciesName(pokemonName string) string {
+	if p, ok := pokedex[pokemonName]; ok && p.Species.Name != "" {
+		return p.Species.Name
+	}
+	return pokemonName
+}
+
+func getEvolutionChain(pokemonName string, cache *pokecache.Cache) (evolution.Chain, error) {
+	var chain evolution.Chain
+	species, err := getSpecies(speciesName(pokemonName), cache)
+	if err != nil {
+		return chain, err
+	}
+	if species.EvolutionChain.URL == "" {
+		return chain, fmt.Errorf("%s has no evolution chain", pokemonName)
+	}
+	err = fetchResource(species.EvolutionChain.URL, cache, &chain)
+	return chain, err
+}
+
+func timeOfDay(t time.Time) string {
+	if h := t.Hour(); h >= 6 && h < 18 {
+		return "day"
+	}
+	return "night"
+}
+
+// evolutionContext describes p for the evolutions in chain. Move types are
+// only looked up when one of p's evolutions asks for a known move type.
+func evolutionContext(config *commandConfig, p *ownedPokemon, chain evolution.Chain) evolution.Context {
+	species := pokedex[p.Species]
+	computed := p.computedStats(species)
+	ctx := evolution.Context{
+		Level:      p.Level,
+		Friendship: p.Friendship,
+		TimeOfDay:  timeOfDay(time.Now()),
+		HeldItem:   p.HeldItem,
+		KnownMoves: knownMoves(species, config.versionGroup, p.Level),
+		Attack:     computed[stats.Attack],
+		Defense:    computed[stats.Defense],
+		Gender:     genderNumber(p.Gender),
+	}
+	if config.area != nil {
+		ctx.Location = config.area.Location.Name
+	}
+	for _, id := range party {
+		if member, ok := owned[id]; ok && id != p.ID {
+			ctx.PartySpecies = append(ctx.PartySpecies, speciesName(member.Species))
+			ctx.PartyTypes = append(ctx.PartyTypes, typeNames(pokedex[member.Species])...)
+		}
+	}
+	for _, step := range chain.Next(speciesName(p.Species)) {
+		for _, d := range step.Details {
+			if d.KnownMoveType.Name != "" && ctx.KnownMoveTypes == nil {
+				ctx.KnownMoveTypes = []string{}
+				for _, name := range ctx.KnownMoves {
+					if move, err := getMove(name, config.cache); err == nil {
+						ctx.KnownMoveTypes = append(ctx.KnownMoveTypes, move.Type.Name)
+					}
+				}
+			}
+		}
+	}
+	return ctx
+}
+
+// evolveOwned turns p into target. An item p had to hold to evolve is used
+// up, as it is in the games.
+func evolveOwned(config *commandConfig, p *ownedPokemon, target string, met evolution.Detail) error {
+	evolved, err := getPokemon(target, config.cache)
+	if err != nil {
+		return err
+	}
+	previous := p.Species
+	pokedex[evolved.Name] = evolved
+	p.Species = evolved.Name
+	if met.HeldItem.Name != "" && p.HeldItem == met.HeldItem.Name {
+		p.HeldItem = ""
+	}
+	fmt.Println(config.t("evolve.done", previous, evolved.Name))
+	return nil
+}
+
+// checkEvolution asks whether an owned Pokemon that has met the conditions
+// for a level-up evolution should evolve now. Without someone to ask, as in
+// a script, it only says how to evolve it later. Lookup failures are not
+// worth interrupting the caller for, so they are ignored.
+func checkEvolution(config *commandConfig, p *ownedPokemon) {
+	if config.battle != nil {
+		return
+	}
+	chain, err := getEvolutionChain(p.Species, config.cache)
+	if err != nil {
+		return
+	}
+	target, met, ok := chain.Match(speciesName(p.Species), evolutionContext(config, p, chain))
+	if !ok {
+		return
+	}
+	if config.confirm == nil || config.structuredOutput() {
+		fmt.Println(config.t("evolve.ready", p.Species, target, p.ID))
+		return
+	}
+	if !config.confirm(config.t("evolve.prompt", p.Species, target)) {
+		fmt.Println(config.t("evolve.stopped", p.Species))
+		return
+	}
+	if err := evolveOwned(config, p, target, met); err != nil {
+		config.reportError(err)
+	}
+}
+
+func commandEvolve(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("evolve command requires a pokemon id")
+	}
+	if config.battle != nil {
+		return fmt.Errorf("you can't evolve a pokemon during a battle")
+	}
+	id, err := parseOwnedID(args[0])
+	if err != nil {
+		return err
+	}
+	p := owned[id]
+	chain, err := getEvolutionChain(p.Species, config.cache)
+	if err != nil {
+		return err
+	}
+	ctx := evolutionContext(config, p, chain)
+	if len(args) > 1 && args[1] == "trade" {
+		ctx.Traded = true
+		if len(args) > 2 {
+			ctx.TradedFor = args[2]
+		}
+	}
+	if target, met, ok := chain.Match(speciesName(p.Species), ctx); ok {
+		return evolveOwned(config, p, target, met)
+	}
+
+	steps := chain.Next(speciesName(p.Species))
+	if len(steps) == 0 {
+		fmt.Println(config.t("evolve.final", p.Species))
+		return nil
+	}
+	fmt.Println(config.t("evolve.not_ready", p.Species))
+	for _, step := range steps {
+		for _, d := range step.Details {
+			fmt.Printf("  - %s: %s\n", step.Species, d)
+		}
+	}
+	return nil
+}
+
+func commandEvolutions(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("evolutions command requires a pokemon name")
+	}
+	chain, err := getEvolutionChain(args[0], config.cache)
+	if err != nil {
+		return err
+	}
+	fmt.Print(chain.Tree())
+	return nil
+}
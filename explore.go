@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/render"
+)
+
+var encounterSortKeys = []string{"chance", "rarity", "name", "level"}
+
+type methodEncounter struct {
+	Name       string   `json:"name"`
+	MinLevel   int      `json:"min_level"`
+	MaxLevel   int      `json:"max_level"`
+	Chance     int      `json:"chance"`
+	Conditions []string `json:"conditions"`
+}
+
+type encounterMethod struct {
+	Method string `json:"method"`
+	// Rate is how often an encounter happens at all with this method.
+	Rate    int               `json:"rate"`
+	Pokemon []methodEncounter `json:"pokemon"`
+}
+
+// encounterMethods groups an area's encounters by method. Chances of the
+// slots a Pokemon fills are summed within a version; across versions the
+// highest sum is kept, and level ranges and conditions are merged.
+func (config *commandConfig) encounterMethods(area *LocationDetails) []encounterMethod {
+	type key struct{ method, pokemon string }
+	merged := map[key]*methodEncounter{}
+	var order []key
+	for _, e := range area.PokemonEncounters {
+		for _, vd := range e.VersionDetails {
+			if config.version != "" && vd.Version.Name != config.version {
+				continue
+			}
+			chances := map[string]int{}
+			for _, d := range vd.EncounterDetails {
+				k := key{d.Method.Name, e.Pokemon.Name}
+				m, ok := merged[k]
+				if !ok {
+					m = &methodEncounter{Name: e.Pokemon.Name, MinLevel: d.MinLevel, Conditions: []string{}}
+					merged[k] = m
+					order = append(order, k)
+				}
+				m.MinLevel = min(m.MinLevel, d.MinLevel)
+				m.MaxLevel = max(m.MaxLevel, d.MaxLevel)
+				for _, c := range d.ConditionValues {
+					if !slices.Contains(m.Conditions, c.Name) {
+						m.Conditions = append(m.Conditions, c.Name)
+					}
+				}
+				chances[d.Method.Name] += d.Chance
+			}
+			for method, chance := range chances {
+				m := merged[key{method, e.Pokemon.Name}]
+				m.Chance = max(m.Chance, chance)
+			}
+		}
+	}
+
+	rates := map[string]int{}
+	var methodOrder []string
+	for _, r := range area.EncounterMethodRates {
+		methodOrder = append(methodOrder, r.EncounterMethod.Name)
+		for _, vd := range r.VersionDetails {
+			if config.version == "" || vd.Version.Name == config.version {
+				rates[r.EncounterMethod.Name] = max(rates[r.EncounterMethod.Name], vd.Rate)
+			}
+		}
+	}
+	byMethod := map[string]*encounterMethod{}
+	for _, k := range order {
+		m, ok := byMethod[k.method]
+		if !ok {
+			m = &encounterMethod{Method: k.method, Rate: rates[k.method]}
+			byMethod[k.method] = m
+			if !slices.Contains(methodOrder, k.method) {
+				methodOrder = append(methodOrder, k.method)
+			}
+		}
+		m.Pokemon = append(m.Pokemon, *merged[k])
+	}
+	var methods []encounterMethod
+	for _, name := range methodOrder {
+		if m, ok := byMethod[name]; ok {
+			methods = append(methods, *m)
+		}
+	}
+	return methods
+}
+
+// sortEncounters orders encounters by a key from encounterSortKeys: chance
+// puts the most common first and rarity the rarest first.
+func sortEncounters(encounters []methodEncounter, key string) error {
+	var less func(a, b methodEncounter) bool
+	switch key {
+	case "", "chance":
+		less = func(a, b methodEncounter) bool { return a.Chance > b.Chance }
+	case "rarity":
+		less = func(a, b methodEncounter) bool { return a.Chance < b.Chance }
+	case "name":
+		less = func(a, b methodEncounter) bool { return a.Name < b.Name }
+	case "level":
+		less = func(a, b methodEncounter) bool { return a.MinLevel < b.MinLevel }
+	default:
+		return fmt.Errorf("can't sort by %s (use %s)", key, strings.Join(encounterSortKeys, ", "))
+	}
+	sort.SliceStable(encounters, func(i, j int) bool {
+		return less(encounters[i], encounters[j])
+	})
+	return nil
+}
+
+func printEncounterMethods(config *commandConfig, methods []encounterMethod) error {
+	for i, m := range methods {
+		if i > 0 {
+			fmt.Println()
+		}
+		heading := m.Method
+		if m.Rate > 0 {
+			heading += config.t("explore.rate", m.Rate)
+		}
+		fmt.Println(config.style.Bold(heading + ":"))
+		table := render.Table{
+			Headers: []string{config.t("column.pokemon"), config.t("column.levels"), config.t("column.chance"), config.t("column.conditions")},
+			Right:   []bool{false, true, true},
+		}
+		for _, e := range m.Pokemon {
+			levels := fmt.Sprintf("%d-%d", e.MinLevel, e.MaxLevel)
+			if e.MinLevel == e.MaxLevel {
+				levels = fmt.Sprint(e.MinLevel)
+			}
+			table.Append(config.speciesDisplayName(e.Name), levels, fmt.Sprintf("%d%%", e.Chance), strings.Join(e.Conditions, ", "))
+		}
+		if err := table.Render(os.Stdout, config.style); err != nil {
+			return err
+		}
+	}
+	return nil
+}
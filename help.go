@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/KindMinotaur/pokedexcli/internal/fuzzy"
+)
+
+const (
+	categoryNavigation = "Navigation"
+	categoryLookup     = "Lookup"
+	categoryCatching   = "Catching"
+	categoryCollection = "Collection"
+	categorySystem     = "System"
+)
+
+var helpCategories = []string{categoryNavigation, categoryLookup, categoryCatching, categoryCollection, categorySystem}
+
+func commandHelp(config *commandConfig, args []string) error {
+	if len(args) > 0 {
+		return commandUsage(config, args[0])
+	}
+	fmt.Println(config.t("welcome"))
+	fmt.Println(config.t("usage"))
+	names := commandNames()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, category := range helpCategories {
+		fmt.Fprintf(w, "\n%s:\n", config.t("category."+category))
+		for _, name := range names {
+			if cmd := commands[name]; cmd.category == category {
+				fmt.Fprintf(w, "  %s\t%s\n", name, cmd.description)
+			}
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(config.settings.Aliases)+len(config.settings.Macros) > 0 {
+		fmt.Println("\n" + config.t("help.aliases"))
+		printDefinitions(config.settings.Aliases, "->")
+		printDefinitions(config.settings.Macros, "->")
+	}
+	fmt.Println("\n" + config.t("help.more"))
+	fmt.Println(config.t("help.output"))
+	return nil
+}
+
+func commandUsage(config *commandConfig, name string) error {
+	if target, ok := config.settings.Aliases[name]; ok {
+		fmt.Println(config.t("help.alias_for", name, target))
+		return nil
+	}
+	if body, ok := config.settings.Macros[name]; ok {
+		fmt.Println(config.t("help.macro_for", name, body))
+		return nil
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		err := fmt.Errorf("unknown command: %s", name)
+		if suggestions := fuzzy.Suggest(name, commandNames(), maxSuggestions); len(suggestions) > 0 {
+			err = fmt.Errorf("%w. Did you mean %s?", err, strings.Join(suggestions, ", "))
+		}
+		return err
+	}
+
+	fmt.Printf("%s: %s\n", cmd.name, cmd.description)
+	fmt.Println(config.t("help.usage", cmd.usage()))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(cmd.args) > 0 {
+		fmt.Fprintln(w, "Arguments:")
+		for _, a := range cmd.args {
+			description := a.description
+			if a.optional {
+				description += " (optional)"
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", a.name, description)
+		}
+	}
+	if len(cmd.flags) > 0 {
+		fmt.Fprintln(w, "Flags:")
+		for _, f := range cmd.flags {
+			flag := "--" + f.name
+			if f.short != "" {
+				flag = "-" + f.short + ", " + flag
+			}
+			if f.value != "" {
+				flag += " <" + f.value + ">"
+			}
+			fmt.Fprintf(w, "  %s\t%s\n", flag, f.description)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if len(cmd.examples) > 0 {
+		fmt.Println(config.t("help.examples"))
+		for _, example := range cmd.examples {
+			fmt.Printf("  %s\n", example)
+		}
+	}
+	return nil
+}
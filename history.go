@@ -0,0 +1,158 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultHistorySize = 1000
+
+type historyEntry struct {
+	Time time.Time
+	Line string
+}
+
+func defaultHistoryPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "history")
+}
+
+// historySize reads the limit from POKEDEX_HISTSIZE, falling back to the
+// default for unset or invalid values.
+func historySize() int {
+	if n, err := strconv.Atoi(os.Getenv("POKEDEX_HISTSIZE")); err == nil && n > 0 {
+		return n
+	}
+	return defaultHistorySize
+}
+
+// loadHistory reads the tab-separated timestamp and command lines of the
+// history file, keeping only the newest limit entries.
+func loadHistory(path string, limit int) ([]historyEntry, int, error) {
+	if path == "" {
+		return nil, 0, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	lines := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines++
+		stamp, line, ok := strings.Cut(scanner.Text(), "\t")
+		if !ok {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, stamp)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, historyEntry{Time: t, Line: line})
+	}
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+	return entries, lines, scanner.Err()
+}
+
+func formatHistoryEntry(e historyEntry) string {
+	return e.Time.Format(time.RFC3339) + "\t" + e.Line + "\n"
+}
+
+// recordHistory appends a line to the in-memory history and the history
+// file. The file is allowed to grow to twice the limit before it is
+// rewritten with just the retained entries.
+func recordHistory(config *commandConfig, line string) error {
+	entry := historyEntry{Time: time.Now(), Line: line}
+	config.history = append(config.history, entry)
+	if len(config.history) > config.historyMax {
+		config.history = config.history[len(config.history)-config.historyMax:]
+	}
+	if config.historyPath == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(config.historyPath), 0o755); err != nil {
+		return err
+	}
+
+	if config.historyFileLines+1 > 2*config.historyMax {
+		var b strings.Builder
+		for _, e := range config.history {
+			b.WriteString(formatHistoryEntry(e))
+		}
+		config.historyFileLines = len(config.history)
+		return os.WriteFile(config.historyPath, []byte(b.String()), 0o600)
+	}
+	f, err := os.OpenFile(config.historyPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	config.historyFileLines++
+	_, err = f.WriteString(formatHistoryEntry(entry))
+	return err
+}
+
+// expandHistory resolves !!, !n and !-n against the history, numbered from
+// 1 like the history command shows. Other lines come back unchanged.
+func expandHistory(entries []historyEntry, line string) (string, bool, error) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "!") || len(trimmed) == 1 {
+		return line, false, nil
+	}
+	ref, rest, _ := strings.Cut(trimmed[1:], " ")
+	var index int
+	if ref == "!" {
+		index = len(entries)
+	} else {
+		n, err := strconv.Atoi(ref)
+		if err != nil {
+			return "", false, fmt.Errorf("%s: event not found", trimmed)
+		}
+		index = n
+		if n < 0 {
+			index = len(entries) + n + 1
+		}
+	}
+	if index < 1 || index > len(entries) {
+		return "", false, fmt.Errorf("!%s: event not found", ref)
+	}
+	expanded := entries[index-1].Line
+	if rest != "" {
+		expanded += " " + rest
+	}
+	return expanded, true, nil
+}
+
+func commandHistory(config *commandConfig, args []string) error {
+	count := len(config.history)
+	if len(args) > 0 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return fmt.Errorf("history takes a positive number of entries to show")
+		}
+		count = min(n, count)
+	}
+	start := len(config.history) - count
+	for i, e := range config.history[start:] {
+		fmt.Printf("%5d  %s  %s\n", start+i+1, e.Time.Format("2006-01-02 15:04:05"), e.Line)
+	}
+	return nil
+}
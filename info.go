@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/i18n"
+)
+
+// flavorText returns the newest Pokedex entry in lang, or in English when
+// there is none, with the line and page breaks from the game text
+// collapsed into spaces.
+func flavorText(species PokemonSpecies, lang string) string {
+	text := ""
+	for _, want := range []string{lang, i18n.Fallback} {
+		for _, entry := range species.FlavorTextEntries {
+			if entry.Language.Name == want {
+				text = entry.FlavorText
+			}
+		}
+		if text != "" {
+			break
+		}
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
+
+func genus(species PokemonSpecies, lang string) string {
+	for _, want := range []string{lang, i18n.Fallback} {
+		for _, g := range species.Genera {
+			if g.Language.Name == want {
+				return g.Genus
+			}
+		}
+	}
+	return ""
+}
+
+func newInfoDoc(config *commandConfig, p Pokemon, species PokemonSpecies) infoDoc {
+	lang := config.language()
+	doc := infoDoc{
+		ID:             p.ID,
+		Name:           p.Name,
+		Genus:          genus(species, lang),
+		Types:          typeNames(p),
+		Height:         float64(p.Height) / 10,
+		Weight:         float64(p.Weight) / 10,
+		BaseExperience: p.BaseExperience,
+		Abilities:      []abilitySlotDoc{},
+		Stats:          []statDoc{},
+		Total:          baseStats(p).Total(),
+		HeldItems:      append([]heldItem{}, config.heldItems(p)...),
+		Forms:          []string{},
+		FlavorText:     flavorText(species, lang),
+	}
+	for _, a := range p.Abilities {
+		doc.Abilities = append(doc.Abilities, abilitySlotDoc{Name: a.Ability.Name, Hidden: a.IsHidden})
+	}
+	for _, stat := range p.Stats {
+		doc.Stats = append(doc.Stats, statDoc{Name: stat.Stat.Name, Base: stat.BaseStat})
+	}
+	for _, v := range species.Varieties {
+		doc.Forms = append(doc.Forms, v.Pokemon.Name)
+	}
+	return doc
+}
+
+func commandInfo(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("info command requires a pokemon name or id")
+	}
+	p, err := getPokemon(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(pokemonNames, args[0], err)
+	}
+	species, err := getSpecies(p.Species.Name, config.cache)
+	if err != nil {
+		return err
+	}
+
+	lang := config.language()
+	if config.structuredOutput() {
+		return config.emit(newInfoDoc(config, p, species))
+	}
+	fmt.Printf("#%d %s", p.ID, displayName(species.Names.in(lang), p.Name))
+	if g := genus(species, lang); g != "" {
+		fmt.Print(config.t("info.genus", g))
+	}
+	fmt.Println()
+	fmt.Println(config.t("types", strings.Join(typeNames(p), "/")))
+	fmt.Println(config.t("info.height", float64(p.Height)/10))
+	fmt.Println(config.t("info.weight", float64(p.Weight)/10))
+	fmt.Println(config.t("info.base_experience", p.BaseExperience))
+
+	fmt.Println(config.t("info.abilities"))
+	for _, a := range p.Abilities {
+		if a.IsHidden {
+			fmt.Printf("  -%s %s\n", a.Ability.Name, config.t("info.hidden"))
+		} else {
+			fmt.Printf("  -%s\n", a.Ability.Name)
+		}
+	}
+
+	fmt.Println(config.t("info.base_stats"))
+	base := baseStats(p)
+	for _, stat := range p.Stats {
+		fmt.Printf("  -%s: %d\n", stat.Stat.Name, stat.BaseStat)
+	}
+	fmt.Printf("  %s\n", config.t("info.total", base.Total()))
+
+	if items := config.heldItems(p); len(items) > 0 {
+		fmt.Println(config.t("info.held_items"))
+		for _, item := range items {
+			fmt.Printf("  -%s (%d%%)\n", item.Name, item.Rarity)
+		}
+	}
+	if len(species.Varieties) > 1 {
+		fmt.Println(config.t("info.forms"))
+		for _, v := range species.Varieties {
+			fmt.Printf("  -%s\n", v.Pokemon.Name)
+		}
+	}
+	if text := flavorText(species, lang); text != "" {
+		fmt.Println(text)
+	}
+	return nil
+}
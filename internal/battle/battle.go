@@ -0,0 +1,386 @@
+package battle
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+type Status string
+
+const (
+	StatusNone      Status = ""
+	StatusBurn      Status = "burn"
+	StatusPoison    Status = "poison"
+	StatusParalysis Status = "paralysis"
+	StatusSleep     Status = "sleep"
+	StatusFreeze    Status = "freeze"
+)
+
+func ParseStatus(ailment string) Status {
+	switch Status(ailment) {
+	case StatusBurn, StatusPoison, StatusParalysis, StatusSleep, StatusFreeze:
+		return Status(ailment)
+	}
+	return StatusNone
+}
+
+type Move struct {
+	Name          string
+	Type          string
+	DamageClass   string
+	Power         int
+	Accuracy      int
+	PP            int
+	Priority      int
+	Ailment       Status
+	AilmentChance int
+}
+
+type MoveSlot struct {
+	Move Move
+	PP   int
+}
+
+var struggle = Move{
+	Name:        "struggle",
+	DamageClass: "physical",
+	Power:       50,
+}
+
+type Combatant struct {
+	Name       string
+	Level      int
+	Types      []string
+	Stats      stats.Spread
+	HP         int
+	Status     Status
+	SleepTurns int
+	Moves      []*MoveSlot
+}
+
+func (c *Combatant) Fainted() bool {
+	return c.HP <= 0
+}
+
+func (c *Combatant) hasPP() bool {
+	for _, m := range c.Moves {
+		if m.PP > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Combatant) speed() int {
+	if c.Status == StatusParalysis {
+		return c.Stats[stats.Speed] / 2
+	}
+	return c.Stats[stats.Speed]
+}
+
+func (c *Combatant) damage(amount int) {
+	c.HP -= amount
+	if c.HP < 0 {
+		c.HP = 0
+	}
+}
+
+type TypeChart interface {
+	Multiplier(attackType string, defenderTypes []string) float64
+}
+
+type neutralChart struct{}
+
+func (neutralChart) Multiplier(string, []string) float64 {
+	return 1
+}
+
+type Outcome int
+
+const (
+	Ongoing Outcome = iota
+	PlayerWon
+	WildWon
+	Fled
+)
+
+type Battle struct {
+	Player  *Combatant
+	Wild    *Combatant
+	Chart   TypeChart
+	Rand    *rand.Rand
+	Turns   int
+	Outcome Outcome
+}
+
+func New(player, wild *Combatant, chart TypeChart, r *rand.Rand) *Battle {
+	if chart == nil {
+		chart = neutralChart{}
+	}
+	return &Battle{
+		Player: player,
+		Wild:   wild,
+		Chart:  chart,
+		Rand:   r,
+	}
+}
+
+// Turn plays one round with the player using the move at moveIndex and the
+// wild Pokemon picking a random move with PP left. It returns the lines that
+// describe what happened, in order.
+func (b *Battle) Turn(moveIndex int) ([]string, error) {
+	if b.Outcome != Ongoing {
+		return nil, fmt.Errorf("the battle is already over")
+	}
+	playerMove := struggle
+	if b.Player.hasPP() {
+		if moveIndex < 0 || moveIndex >= len(b.Player.Moves) {
+			return nil, fmt.Errorf("no move in slot %d", moveIndex+1)
+		}
+		slot := b.Player.Moves[moveIndex]
+		if slot.PP <= 0 {
+			return nil, fmt.Errorf("%s has no PP left", slot.Move.Name)
+		}
+		slot.PP--
+		playerMove = slot.Move
+	}
+	wildMove := b.pickWildMove()
+
+	b.Turns++
+	var log []string
+	first, firstMove, second, secondMove := b.Player, playerMove, b.Wild, wildMove
+	if b.wildMovesFirst(playerMove, wildMove) {
+		first, firstMove, second, secondMove = b.Wild, wildMove, b.Player, playerMove
+	}
+
+	log = append(log, b.act(first, second, firstMove)...)
+	if !second.Fainted() && !first.Fainted() {
+		log = append(log, b.act(second, first, secondMove)...)
+	}
+	for _, c := range []*Combatant{first, second} {
+		if !c.Fainted() {
+			log = append(log, b.endOfTurn(c)...)
+		}
+	}
+	log = append(log, b.checkOutcome()...)
+	return log, nil
+}
+
+// Run tries to flee using the games' escape odds, which favour the faster
+// Pokemon and improve with every attempt.
+func (b *Battle) Run() ([]string, error) {
+	if b.Outcome != Ongoing {
+		return nil, fmt.Errorf("the battle is already over")
+	}
+	b.Turns++
+	wildSpeed := b.Wild.speed() / 4 % 256
+	odds := 256
+	if wildSpeed > 0 {
+		odds = b.Player.speed()*32/wildSpeed + 30*b.Turns
+	}
+	if odds >= 256 || b.Rand.Intn(256) < odds {
+		b.Outcome = Fled
+		return []string{"Got away safely!"}, nil
+	}
+	return append([]string{"Couldn't get away!"}, b.wildOnlyTurn()...), nil
+}
+
+// Skip gives up the player's move for this turn, e.g. after throwing a ball
+// or using an item, and lets the wild Pokemon act on its own.
+func (b *Battle) Skip() ([]string, error) {
+	if b.Outcome != Ongoing {
+		return nil, fmt.Errorf("the battle is already over")
+	}
+	b.Turns++
+	return b.wildOnlyTurn(), nil
+}
+
+func (b *Battle) wildOnlyTurn() []string {
+	log := b.act(b.Wild, b.Player, b.pickWildMove())
+	for _, c := range []*Combatant{b.Wild, b.Player} {
+		if !c.Fainted() {
+			log = append(log, b.endOfTurn(c)...)
+		}
+	}
+	return append(log, b.checkOutcome()...)
+}
+
+func (b *Battle) pickWildMove() Move {
+	var usable []*MoveSlot
+	for _, m := range b.Wild.Moves {
+		if m.PP > 0 {
+			usable = append(usable, m)
+		}
+	}
+	if len(usable) == 0 {
+		return struggle
+	}
+	slot := usable[b.Rand.Intn(len(usable))]
+	slot.PP--
+	return slot.Move
+}
+
+func (b *Battle) wildMovesFirst(playerMove, wildMove Move) bool {
+	if playerMove.Priority != wildMove.Priority {
+		return wildMove.Priority > playerMove.Priority
+	}
+	ps, ws := b.Player.speed(), b.Wild.speed()
+	if ps != ws {
+		return ws > ps
+	}
+	return b.Rand.Intn(2) == 0
+}
+
+func (b *Battle) act(attacker, defender *Combatant, move Move) []string {
+	var log []string
+	switch attacker.Status {
+	case StatusSleep:
+		if attacker.SleepTurns > 0 {
+			attacker.SleepTurns--
+			return []string{fmt.Sprintf("%s is fast asleep.", attacker.Name)}
+		}
+		attacker.Status = StatusNone
+		log = append(log, fmt.Sprintf("%s woke up!", attacker.Name))
+	case StatusFreeze:
+		if b.Rand.Intn(5) != 0 {
+			return []string{fmt.Sprintf("%s is frozen solid!", attacker.Name)}
+		}
+		attacker.Status = StatusNone
+		log = append(log, fmt.Sprintf("%s thawed out!", attacker.Name))
+	case StatusParalysis:
+		if b.Rand.Intn(4) == 0 {
+			return []string{fmt.Sprintf("%s is paralyzed! It can't move!", attacker.Name)}
+		}
+	}
+
+	log = append(log, fmt.Sprintf("%s used %s!", attacker.Name, move.Name))
+	if move.Accuracy > 0 && b.Rand.Intn(100) >= move.Accuracy {
+		return append(log, fmt.Sprintf("%s's attack missed!", attacker.Name))
+	}
+
+	if move.DamageClass != "status" && move.Power > 0 {
+		multiplier := 1.0
+		if move.Type != "" {
+			multiplier = b.Chart.Multiplier(move.Type, defender.Types)
+		}
+		if multiplier == 0 {
+			return append(log, fmt.Sprintf("It doesn't affect %s...", defender.Name))
+		}
+		crit := b.Rand.Intn(24) == 0
+		dmg := Damage(attacker, defender, move, multiplier, crit, 85+b.Rand.Intn(16))
+		defender.damage(dmg)
+		if crit {
+			log = append(log, "A critical hit!")
+		}
+		switch {
+		case multiplier > 1:
+			log = append(log, "It's super effective!")
+		case multiplier < 1:
+			log = append(log, "It's not very effective...")
+		}
+		log = append(log, fmt.Sprintf("%s took %d damage (%d HP left).", defender.Name, dmg, defender.HP))
+		if move.Name == struggle.Name {
+			recoil := max(1, attacker.Stats[stats.HP]/4)
+			attacker.damage(recoil)
+			log = append(log, fmt.Sprintf("%s is damaged by recoil!", attacker.Name))
+		}
+	}
+
+	if move.Ailment != StatusNone && !defender.Fainted() && defender.Status == StatusNone {
+		chance := move.AilmentChance
+		if chance == 0 {
+			chance = 100
+		}
+		if b.Rand.Intn(100) < chance {
+			defender.Status = move.Ailment
+			if move.Ailment == StatusSleep {
+				defender.SleepTurns = 1 + b.Rand.Intn(3)
+			}
+			log = append(log, fmt.Sprintf("%s is afflicted with %s!", defender.Name, move.Ailment))
+		}
+	} else if move.DamageClass == "status" && move.Ailment == StatusNone {
+		log = append(log, "But nothing happened!")
+	}
+	return log
+}
+
+func (b *Battle) endOfTurn(c *Combatant) []string {
+	var dmg int
+	switch c.Status {
+	case StatusBurn:
+		dmg = max(1, c.Stats[stats.HP]/16)
+	case StatusPoison:
+		dmg = max(1, c.Stats[stats.HP]/8)
+	default:
+		return nil
+	}
+	c.damage(dmg)
+	return []string{fmt.Sprintf("%s is hurt by its %s! (%d HP left)", c.Name, c.Status, c.HP)}
+}
+
+func (b *Battle) checkOutcome() []string {
+	switch {
+	case b.Wild.Fainted():
+		b.Outcome = PlayerWon
+		return []string{fmt.Sprintf("The wild %s fainted!", b.Wild.Name)}
+	case b.Player.Fainted():
+		b.Outcome = WildWon
+		return []string{fmt.Sprintf("%s fainted!", b.Player.Name)}
+	}
+	return nil
+}
+
+// Damage applies the generation V+ damage formula. roll is the random factor
+// as a percentage between 85 and 100.
+func Damage(attacker, defender *Combatant, move Move, typeMultiplier float64, crit bool, roll int) int {
+	atk, def := attacker.Stats[stats.Attack], defender.Stats[stats.Defense]
+	if move.DamageClass == "special" {
+		atk, def = attacker.Stats[stats.SpAttack], defender.Stats[stats.SpDefense]
+	}
+	if def < 1 {
+		def = 1
+	}
+	dmg := float64((2*attacker.Level/5+2)*move.Power*atk/def/50 + 2)
+	if crit {
+		dmg *= 1.5
+	}
+	dmg = float64(int(dmg) * roll / 100)
+	for _, t := range attacker.Types {
+		if t == move.Type {
+			dmg *= 1.5
+			break
+		}
+	}
+	dmg *= typeMultiplier
+	if attacker.Status == StatusBurn && move.DamageClass == "physical" {
+		dmg /= 2
+	}
+	if int(dmg) < 1 {
+		return 1
+	}
+	return int(dmg)
+}
+
+// CatchRateMultiplier scales the odds of a catch by how weakened the target
+// is: 1 at full health, approaching 3 as HP drops towards zero.
+func CatchRateMultiplier(hp, maxHP int) float64 {
+	if maxHP <= 0 || hp >= maxHP {
+		return 1
+	}
+	if hp < 1 {
+		hp = 1
+	}
+	return float64(3*maxHP-2*hp) / float64(maxHP)
+}
+
+func StatusCatchBonus(s Status) float64 {
+	switch s {
+	case StatusSleep, StatusFreeze:
+		return 2
+	case StatusParalysis, StatusPoison, StatusBurn:
+		return 1.5
+	}
+	return 1
+}
@@ -0,0 +1,148 @@
+package battle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+type fixedChart map[string]float64
+
+func (c fixedChart) Multiplier(attackType string, defenderTypes []string) float64 {
+	if m, ok := c[attackType]; ok {
+		return m
+	}
+	return 1
+}
+
+var tackle = Move{Name: "tackle", Type: "normal", DamageClass: "physical", Power: 40, Accuracy: 100, PP: 35}
+
+func newCombatant(name string, level int, types []string, moves ...Move) *Combatant {
+	c := &Combatant{
+		Name:  name,
+		Level: level,
+		Types: types,
+		Stats: stats.Spread{100, 50, 50, 50, 50, 50},
+		HP:    100,
+	}
+	for _, m := range moves {
+		c.Moves = append(c.Moves, &MoveSlot{Move: m, PP: m.PP})
+	}
+	return c
+}
+
+func TestDamage(t *testing.T) {
+	attacker := newCombatant("a", 50, []string{"normal"})
+	defender := newCombatant("d", 50, []string{"normal"})
+
+	cases := []struct {
+		multiplier float64
+		crit       bool
+		roll       int
+		expected   int
+	}{
+		{multiplier: 1, roll: 100, expected: 28},
+		{multiplier: 1, roll: 85, expected: 24},
+		{multiplier: 2, roll: 100, expected: 57},
+		{multiplier: 0.5, roll: 100, expected: 14},
+		{multiplier: 1, crit: true, roll: 100, expected: 42},
+	}
+	for _, c := range cases {
+		actual := Damage(attacker, defender, tackle, c.multiplier, c.crit, c.roll)
+		if actual != c.expected {
+			t.Errorf("Damage(x%v, crit=%v, roll=%d): expected %d, got %d", c.multiplier, c.crit, c.roll, c.expected, actual)
+		}
+	}
+
+	attacker.Status = StatusBurn
+	if actual := Damage(attacker, defender, tackle, 1, false, 100); actual != 14 {
+		t.Errorf("expected burn to halve physical damage, got %d", actual)
+	}
+}
+
+func TestTurn(t *testing.T) {
+	player := newCombatant("pikachu", 50, []string{"electric"}, tackle)
+	wild := newCombatant("pidgey", 5, []string{"normal", "flying"}, tackle)
+	b := New(player, wild, nil, rand.New(rand.NewSource(1)))
+
+	for b.Outcome == Ongoing {
+		if _, err := b.Turn(0); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if b.Turns > 100 {
+			t.Fatalf("battle did not finish")
+		}
+	}
+	if b.Outcome != PlayerWon {
+		t.Errorf("expected the level 50 player to win, got %v", b.Outcome)
+	}
+	if player.Moves[0].PP != tackle.PP-b.Turns {
+		t.Errorf("expected PP to drop by one per turn, got %d", player.Moves[0].PP)
+	}
+	if _, err := b.Turn(0); err == nil {
+		t.Errorf("expected an error after the battle ended")
+	}
+}
+
+func TestTurnImmune(t *testing.T) {
+	ghostOnly := fixedChart{"normal": 0}
+	player := newCombatant("rattata", 50, []string{"normal"}, tackle)
+	wild := newCombatant("gastly", 50, []string{"ghost"}, Move{Name: "splash", DamageClass: "status", PP: 40})
+	b := New(player, wild, ghostOnly, rand.New(rand.NewSource(1)))
+
+	if _, err := b.Turn(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wild.HP != 100 {
+		t.Errorf("expected an immune defender to take no damage, has %d HP", wild.HP)
+	}
+}
+
+func TestStatusMove(t *testing.T) {
+	thunderWave := Move{Name: "thunder-wave", Type: "electric", DamageClass: "status", PP: 20, Ailment: StatusParalysis}
+	player := newCombatant("pikachu", 50, []string{"electric"}, thunderWave)
+	wild := newCombatant("pidgey", 50, []string{"normal"}, Move{Name: "splash", DamageClass: "status", PP: 40})
+	b := New(player, wild, nil, rand.New(rand.NewSource(1)))
+
+	if _, err := b.Turn(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if wild.Status != StatusParalysis {
+		t.Errorf("expected the wild pokemon to be paralyzed, got %q", wild.Status)
+	}
+	if wild.speed() != 25 {
+		t.Errorf("expected paralysis to halve speed, got %d", wild.speed())
+	}
+}
+
+func TestStruggle(t *testing.T) {
+	player := newCombatant("pikachu", 50, []string{"electric"}, Move{Name: "tackle", Type: "normal", DamageClass: "physical", Power: 40, PP: 1})
+	player.Moves[0].PP = 0
+	wild := newCombatant("pidgey", 50, []string{"normal"}, Move{Name: "splash", DamageClass: "status", PP: 40})
+	b := New(player, wild, nil, rand.New(rand.NewSource(1)))
+
+	if _, err := b.Turn(0); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if player.HP != 75 {
+		t.Errorf("expected struggle recoil of 25, has %d HP", player.HP)
+	}
+}
+
+func TestCatchRateMultiplier(t *testing.T) {
+	cases := []struct {
+		hp, maxHP int
+		expected  float64
+	}{
+		{hp: 100, maxHP: 100, expected: 1},
+		{hp: 50, maxHP: 100, expected: 2},
+		{hp: 1, maxHP: 100, expected: 2.98},
+		{hp: 0, maxHP: 0, expected: 1},
+	}
+	for _, c := range cases {
+		if actual := CatchRateMultiplier(c.hp, c.maxHP); actual != c.expected {
+			t.Errorf("CatchRateMultiplier(%d, %d): expected %v, got %v", c.hp, c.maxHP, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,336 @@
+package evolution
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	TriggerLevelUp = "level-up"
+	TriggerUseItem = "use-item"
+	TriggerTrade   = "trade"
+)
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Detail struct {
+	Trigger      namedResource `json:"trigger"`
+	MinLevel     int           `json:"min_level"`
+	MinHappiness int           `json:"min_happiness"`
+	// MinAffection and MinBeauty are not tracked, so they can never be met.
+	MinAffection  int           `json:"min_affection"`
+	MinBeauty     int           `json:"min_beauty"`
+	Item          namedResource `json:"item"`
+	HeldItem      namedResource `json:"held_item"`
+	KnownMove     namedResource `json:"known_move"`
+	KnownMoveType namedResource `json:"known_move_type"`
+	TimeOfDay     string        `json:"time_of_day"`
+	Location      namedResource `json:"location"`
+	// Gender is 1 for female and 2 for male.
+	Gender *int `json:"gender"`
+	// RelativePhysicalStats compares Attack with Defense: 1 for higher,
+	// -1 for lower and 0 for equal.
+	RelativePhysicalStats *int          `json:"relative_physical_stats"`
+	PartySpecies          namedResource `json:"party_species"`
+	PartyType             namedResource `json:"party_type"`
+	TradeSpecies          namedResource `json:"trade_species"`
+	NeedsOverworldRain    bool          `json:"needs_overworld_rain"`
+	TurnUpsideDown        bool          `json:"turn_upside_down"`
+	// Unknown lists conditions this package doesn't understand. A detail
+	// with any of them is never met.
+	Unknown []string `json:"-"`
+}
+
+var knownFields = map[string]bool{
+	"trigger": true, "min_level": true, "min_happiness": true, "min_affection": true, "min_beauty": true,
+	"item": true, "held_item": true, "known_move": true, "known_move_type": true, "time_of_day": true,
+	"location": true, "gender": true, "relative_physical_stats": true, "party_species": true,
+	"party_type": true, "trade_species": true, "needs_overworld_rain": true, "turn_upside_down": true,
+}
+
+func (d *Detail) UnmarshalJSON(data []byte) error {
+	type plain Detail
+	if err := json.Unmarshal(data, (*plain)(d)); err != nil {
+		return err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	d.Unknown = nil
+	for name, value := range fields {
+		if knownFields[name] {
+			continue
+		}
+		switch string(value) {
+		case "null", "false", "0", `""`:
+			continue
+		}
+		d.Unknown = append(d.Unknown, name)
+	}
+	sort.Strings(d.Unknown)
+	return nil
+}
+
+type Link struct {
+	Species          namedResource `json:"species"`
+	EvolutionDetails []Detail      `json:"evolution_details"`
+	EvolvesTo        []Link        `json:"evolves_to"`
+}
+
+type Chain struct {
+	ID    int  `json:"id"`
+	Chain Link `json:"chain"`
+}
+
+// Context describes the owned Pokemon and the circumstances an evolution is
+// being attempted under.
+type Context struct {
+	Level      int
+	Friendship int
+	TimeOfDay  string
+	UsedItem   string
+	HeldItem   string
+	Traded     bool
+	// TradedFor is the species received in the trade, when known.
+	TradedFor      string
+	KnownMoves     []string
+	KnownMoveTypes []string
+	// Location is the location the Pokemon is currently at.
+	Location string
+	// Gender follows Detail.Gender; 0 means unknown.
+	Gender  int
+	Attack  int
+	Defense int
+	// PartySpecies and PartyTypes describe the rest of the party.
+	PartySpecies []string
+	PartyTypes   []string
+}
+
+type Step struct {
+	Species string
+	Details []Detail
+}
+
+func (c Chain) find(species string) (Link, bool) {
+	var walk func(l Link) (Link, bool)
+	walk = func(l Link) (Link, bool) {
+		if l.Species.Name == species {
+			return l, true
+		}
+		for _, next := range l.EvolvesTo {
+			if found, ok := walk(next); ok {
+				return found, true
+			}
+		}
+		return Link{}, false
+	}
+	return walk(c.Chain)
+}
+
+func (c Chain) Next(species string) []Step {
+	link, ok := c.find(species)
+	if !ok {
+		return nil
+	}
+	steps := make([]Step, 0, len(link.EvolvesTo))
+	for _, next := range link.EvolvesTo {
+		steps = append(steps, Step{Species: next.Species.Name, Details: next.EvolutionDetails})
+	}
+	return steps
+}
+
+// Ready returns the first evolution whose conditions ctx satisfies.
+func (c Chain) Ready(species string, ctx Context) (string, bool) {
+	target, _, ok := c.Match(species, ctx)
+	return target, ok
+}
+
+// Match is Ready that also returns the conditions that were met, so the
+// caller can tell whether a held item is used up.
+func (c Chain) Match(species string, ctx Context) (string, Detail, bool) {
+	for _, step := range c.Next(species) {
+		for _, d := range step.Details {
+			if d.Met(ctx) {
+				return step.Species, d, true
+			}
+		}
+	}
+	return "", Detail{}, false
+}
+
+// Met reports whether ctx satisfies every condition of d. Conditions the
+// app can't track, such as affection or overworld rain, are never met.
+func (d Detail) Met(ctx Context) bool {
+	if len(d.Unknown) > 0 || d.MinAffection > 0 || d.MinBeauty > 0 || d.NeedsOverworldRain || d.TurnUpsideDown {
+		return false
+	}
+	switch d.Trigger.Name {
+	case TriggerLevelUp:
+		if ctx.UsedItem != "" || ctx.Traded {
+			return false
+		}
+	case TriggerUseItem:
+		if ctx.UsedItem == "" || ctx.UsedItem != d.Item.Name {
+			return false
+		}
+	case TriggerTrade:
+		if !ctx.Traded {
+			return false
+		}
+	default:
+		return false
+	}
+	if d.MinLevel > 0 && ctx.Level < d.MinLevel {
+		return false
+	}
+	if d.MinHappiness > 0 && ctx.Friendship < d.MinHappiness {
+		return false
+	}
+	if d.HeldItem.Name != "" && ctx.HeldItem != d.HeldItem.Name {
+		return false
+	}
+	if d.TimeOfDay != "" && ctx.TimeOfDay != d.TimeOfDay {
+		return false
+	}
+	if d.KnownMove.Name != "" && !slices.Contains(ctx.KnownMoves, d.KnownMove.Name) {
+		return false
+	}
+	if d.KnownMoveType.Name != "" && !slices.Contains(ctx.KnownMoveTypes, d.KnownMoveType.Name) {
+		return false
+	}
+	if d.Location.Name != "" && ctx.Location != d.Location.Name {
+		return false
+	}
+	if d.Gender != nil && ctx.Gender != *d.Gender {
+		return false
+	}
+	if d.RelativePhysicalStats != nil && compare(ctx.Attack, ctx.Defense) != *d.RelativePhysicalStats {
+		return false
+	}
+	if d.PartySpecies.Name != "" && !slices.Contains(ctx.PartySpecies, d.PartySpecies.Name) {
+		return false
+	}
+	if d.PartyType.Name != "" && !slices.Contains(ctx.PartyTypes, d.PartyType.Name) {
+		return false
+	}
+	if d.TradeSpecies.Name != "" && ctx.TradedFor != d.TradeSpecies.Name {
+		return false
+	}
+	return true
+}
+
+func compare(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+func (d Detail) String() string {
+	var parts []string
+	switch d.Trigger.Name {
+	case TriggerUseItem:
+		parts = append(parts, "use "+d.Item.Name)
+	case TriggerTrade:
+		parts = append(parts, "trade")
+	case TriggerLevelUp:
+		if d.MinLevel > 0 {
+			parts = append(parts, fmt.Sprintf("level %d", d.MinLevel))
+		} else {
+			parts = append(parts, "level up")
+		}
+	default:
+		parts = append(parts, d.Trigger.Name)
+	}
+	if d.MinHappiness > 0 {
+		parts = append(parts, fmt.Sprintf("friendship %d", d.MinHappiness))
+	}
+	if d.HeldItem.Name != "" {
+		parts = append(parts, "holding "+d.HeldItem.Name)
+	}
+	if d.KnownMove.Name != "" {
+		parts = append(parts, "knowing "+d.KnownMove.Name)
+	}
+	if d.TimeOfDay != "" {
+		parts = append(parts, "during the "+d.TimeOfDay)
+	}
+	if d.KnownMoveType.Name != "" {
+		parts = append(parts, "knowing a "+d.KnownMoveType.Name+" move")
+	}
+	if d.MinAffection > 0 {
+		parts = append(parts, fmt.Sprintf("affection %d", d.MinAffection))
+	}
+	if d.MinBeauty > 0 {
+		parts = append(parts, fmt.Sprintf("beauty %d", d.MinBeauty))
+	}
+	if d.Gender != nil {
+		parts = append(parts, map[int]string{1: "female", 2: "male"}[*d.Gender])
+	}
+	if d.RelativePhysicalStats != nil {
+		parts = append(parts, map[int]string{1: "attack > defense", -1: "attack < defense", 0: "attack = defense"}[*d.RelativePhysicalStats])
+	}
+	if d.PartySpecies.Name != "" {
+		parts = append(parts, "with "+d.PartySpecies.Name+" in the party")
+	}
+	if d.PartyType.Name != "" {
+		parts = append(parts, "with a "+d.PartyType.Name+" type in the party")
+	}
+	if d.TradeSpecies.Name != "" {
+		parts = append(parts, "for "+d.TradeSpecies.Name)
+	}
+	if d.NeedsOverworldRain {
+		parts = append(parts, "in the rain")
+	}
+	if d.TurnUpsideDown {
+		parts = append(parts, "upside down")
+	}
+	if d.Location.Name != "" {
+		parts = append(parts, "at "+d.Location.Name)
+	}
+	for _, name := range d.Unknown {
+		parts = append(parts, strings.ReplaceAll(name, "_", " "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func describeDetails(details []Detail) string {
+	parts := make([]string, 0, len(details))
+	seen := map[string]bool{}
+	for _, d := range details {
+		s := d.String()
+		if !seen[s] {
+			seen[s] = true
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, " or ")
+}
+
+// Tree renders the chain with box-drawing branches, one species per line.
+func (c Chain) Tree() string {
+	var b strings.Builder
+	b.WriteString(c.Chain.Species.Name + "\n")
+	var walk func(links []Link, prefix string)
+	walk = func(links []Link, prefix string) {
+		for i, l := range links {
+			branch, indent := "├── ", "│   "
+			if i == len(links)-1 {
+				branch, indent = "└── ", "    "
+			}
+			fmt.Fprintf(&b, "%s%s%s (%s)\n", prefix, branch, l.Species.Name, describeDetails(l.EvolutionDetails))
+			walk(l.EvolvesTo, prefix+indent)
+		}
+	}
+	walk(c.Chain.EvolvesTo, "")
+	return b.String()
+}
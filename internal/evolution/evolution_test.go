@@ -0,0 +1,102 @@
+package evolution
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const eeveeJSON = `{"id":67,"chain":{"species":{"name":"eevee"},"evolution_details":[],"evolves_to":[
+	{"species":{"name":"vaporeon"},"evolution_details":[{"trigger":{"name":"use-item"},"item":{"name":"water-stone"}}],"evolves_to":[]},
+	{"species":{"name":"espeon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_happiness":160,"time_of_day":"day"}],"evolves_to":[]},
+	{"species":{"name":"umbreon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_happiness":160,"time_of_day":"night"}],"evolves_to":[]},
+	{"species":{"name":"leafeon"},"evolution_details":[{"trigger":{"name":"level-up"},"location":{"name":"eterna-forest"},"gender":null}],"evolves_to":[]},
+	{"species":{"name":"glaceon"},"evolution_details":[{"trigger":{"name":"level-up"},"location":{"name":"sinnoh-route-217"}}],"evolves_to":[]},
+	{"species":{"name":"sylveon"},"evolution_details":[{"trigger":{"name":"level-up"},"min_affection":2,"known_move_type":{"name":"fairy"}}],"evolves_to":[]}]}}`
+
+const tyrogueJSON = `{"id":47,"chain":{"species":{"name":"tyrogue"},"evolution_details":[],"evolves_to":[
+	{"species":{"name":"hitmonlee"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":20,"relative_physical_stats":1}],"evolves_to":[]},
+	{"species":{"name":"hitmonchan"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":20,"relative_physical_stats":-1}],"evolves_to":[]},
+	{"species":{"name":"hitmontop"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":20,"relative_physical_stats":0}],"evolves_to":[]}]}}`
+
+const machopJSON = `{"id":30,"chain":{"species":{"name":"machop"},"evolution_details":[],"evolves_to":[
+	{"species":{"name":"machoke"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":28}],"evolves_to":[
+		{"species":{"name":"machamp"},"evolution_details":[{"trigger":{"name":"trade"}}],"evolves_to":[]}]}]}}`
+
+func parseChain(t *testing.T, doc string) Chain {
+	t.Helper()
+	var chain Chain
+	if err := json.Unmarshal([]byte(doc), &chain); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return chain
+}
+
+func TestReady(t *testing.T) {
+	eevee := parseChain(t, eeveeJSON)
+	machop := parseChain(t, machopJSON)
+	tyrogue := parseChain(t, tyrogueJSON)
+
+	cases := []struct {
+		chain    Chain
+		species  string
+		ctx      Context
+		expected string
+	}{
+		{chain: eevee, species: "eevee", ctx: Context{Level: 10, Friendship: 70, TimeOfDay: "day"}, expected: ""},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 10, Friendship: 200, TimeOfDay: "night"}, expected: "umbreon"},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 10, UsedItem: "water-stone"}, expected: "vaporeon"},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 10, UsedItem: "fire-stone"}, expected: ""},
+		{chain: machop, species: "machop", ctx: Context{Level: 27}, expected: ""},
+		{chain: machop, species: "machop", ctx: Context{Level: 28}, expected: "machoke"},
+		{chain: machop, species: "machoke", ctx: Context{Level: 40}, expected: ""},
+		{chain: machop, species: "machoke", ctx: Context{Level: 40, Traded: true}, expected: "machamp"},
+		{chain: machop, species: "machamp", ctx: Context{Level: 100, Traded: true}, expected: ""},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 30, Friendship: 70, TimeOfDay: "night"}, expected: ""},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 30, Location: "eterna-forest"}, expected: "leafeon"},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 30, Location: "sinnoh-route-217"}, expected: "glaceon"},
+		{chain: eevee, species: "eevee", ctx: Context{Level: 30, KnownMoveTypes: []string{"fairy"}}, expected: ""},
+		{chain: tyrogue, species: "tyrogue", ctx: Context{Level: 19, Attack: 30, Defense: 20}, expected: ""},
+		{chain: tyrogue, species: "tyrogue", ctx: Context{Level: 20, Attack: 30, Defense: 20}, expected: "hitmonlee"},
+		{chain: tyrogue, species: "tyrogue", ctx: Context{Level: 20, Attack: 20, Defense: 30}, expected: "hitmonchan"},
+		{chain: tyrogue, species: "tyrogue", ctx: Context{Level: 20, Attack: 25, Defense: 25}, expected: "hitmontop"},
+	}
+	for _, c := range cases {
+		actual, _ := c.chain.Ready(c.species, c.ctx)
+		if actual != c.expected {
+			t.Errorf("%s with %+v: expected %q, got %q", c.species, c.ctx, c.expected, actual)
+		}
+	}
+}
+
+func TestTree(t *testing.T) {
+	expected := "machop\n" +
+		"└── machoke (level 28)\n" +
+		"    └── machamp (trade)\n"
+	if actual := parseChain(t, machopJSON).Tree(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+
+	expected = "eevee\n" +
+		"├── vaporeon (use water-stone)\n" +
+		"├── espeon (level up, friendship 160, during the day)\n" +
+		"├── umbreon (level up, friendship 160, during the night)\n" +
+		"├── leafeon (level up, at eterna-forest)\n" +
+		"├── glaceon (level up, at sinnoh-route-217)\n" +
+		"└── sylveon (level up, knowing a fairy move, affection 2)\n"
+	if actual := parseChain(t, eeveeJSON).Tree(); actual != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestUnknownCondition(t *testing.T) {
+	var d Detail
+	if err := json.Unmarshal([]byte(`{"trigger":{"name":"level-up"},"min_steps":1000,"region":null}`), &d); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if d.Met(Context{Level: 100}) {
+		t.Errorf("Expected a detail with an unknown condition to be unmet")
+	}
+	if actual := d.String(); actual != "level up, min steps" {
+		t.Errorf("Expected the unknown condition to be described, got %q", actual)
+	}
+}
@@ -0,0 +1,77 @@
+package fuzzy
+
+import (
+	"sort"
+	"strings"
+)
+
+// Distance is the Levenshtein edit distance between a and b.
+func Distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+type match struct {
+	name  string
+	score int
+}
+
+// Suggest ranks candidates that contain query first, shortest first, then
+// those within a few edits of it. At most limit names are returned.
+func Suggest(query string, candidates []string, limit int) []string {
+	threshold := max(2, len(query)/3)
+	var matches []match
+	for _, c := range candidates {
+		if c == query {
+			continue
+		}
+		if strings.Contains(c, query) {
+			matches = append(matches, match{name: c, score: -1000 + len(c)})
+			continue
+		}
+		if d := Distance(query, c); d <= threshold {
+			matches = append(matches, match{name: c, score: d})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score < matches[j].score
+		}
+		return matches[i].name < matches[j].name
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	names := make([]string, 0, len(matches))
+	for _, m := range matches {
+		names = append(names, m.name)
+	}
+	return names
+}
+
+func Search(substring string, candidates []string) []string {
+	var found []string
+	for _, c := range candidates {
+		if strings.Contains(c, substring) {
+			found = append(found, c)
+		}
+	}
+	sort.Strings(found)
+	return found
+}
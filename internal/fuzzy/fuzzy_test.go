@@ -0,0 +1,52 @@
+package fuzzy
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "abc", expected: 3},
+		{a: "pikachu", b: "pikachu", expected: 0},
+		{a: "pikachoo", b: "pikachu", expected: 2},
+		{a: "kitten", b: "sitting", expected: 3},
+		{a: "flabébé", b: "flabebe", expected: 2},
+	}
+	for _, c := range cases {
+		if actual := Distance(c.a, c.b); actual != c.expected {
+			t.Errorf("Distance(%q, %q): expected %d, got %d", c.a, c.b, c.expected, actual)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"pikachu", "pichu", "raichu", "canalave-city-area", "eterna-city-area", "pikipek"}
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "pikachoo", expected: []string{"pikachu"}},
+		{query: "canalave", expected: []string{"canalave-city-area"}},
+		{query: "raichoo", expected: []string{"raichu"}},
+		{query: "city", expected: []string{"eterna-city-area", "canalave-city-area"}},
+		{query: "zzzzzz", expected: []string{}},
+	}
+	for _, c := range cases {
+		actual := Suggest(c.query, candidates, 3)
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("Suggest(%q): expected %v, got %v", c.query, c.expected, actual)
+		}
+	}
+}
+
+func TestSearch(t *testing.T) {
+	actual := Search("chu", []string{"raichu", "pikachu", "bulbasaur"})
+	expected := []string{"pikachu", "raichu"}
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
@@ -0,0 +1,68 @@
+// Package i18n translates the app's own messages. Each language has a JSON
+// file in locales mapping message keys to fmt format strings; English is
+// complete and is used for anything a language leaves out. The catalog
+// covers what commands print; error messages and the battle log are only
+// in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+const Fallback = "en"
+
+//go:embed locales/*.json
+var files embed.FS
+
+var catalog = mustLoad()
+
+func mustLoad() map[string]map[string]string {
+	entries, err := files.ReadDir("locales")
+	if err != nil {
+		panic(err)
+	}
+	messages := map[string]map[string]string{}
+	for _, entry := range entries {
+		data, err := files.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			panic(err)
+		}
+		var m map[string]string
+		if err := json.Unmarshal(data, &m); err != nil {
+			panic(fmt.Sprintf("locales/%s: %v", entry.Name(), err))
+		}
+		messages[strings.TrimSuffix(entry.Name(), ".json")] = m
+	}
+	return messages
+}
+
+// Languages lists the languages with a catalog.
+func Languages() []string {
+	langs := make([]string, 0, len(catalog))
+	for lang := range catalog {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	return langs
+}
+
+// T formats the message for key in lang, falling back to English and then
+// to the key itself.
+func T(lang, key string, args ...any) string {
+	format, ok := catalog[lang][key]
+	if !ok {
+		format, ok = catalog[Fallback][key]
+	}
+	if !ok {
+		format = key
+	}
+	if len(args) == 0 {
+		return format
+	}
+	return fmt.Sprintf(format, args...)
+}
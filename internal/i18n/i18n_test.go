@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"regexp"
+	"testing"
+)
+
+var verb = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z]`)
+
+// TestCatalogs checks every translation is for a known key and uses the
+// same format verbs as the English message.
+func TestCatalogs(t *testing.T) {
+	english := catalog[Fallback]
+	for lang, messages := range catalog {
+		for key, format := range messages {
+			original, ok := english[key]
+			if !ok {
+				t.Errorf("%s: %s is not an English message", lang, key)
+				continue
+			}
+			if a, b := verb.FindAllString(format, -1), verb.FindAllString(original, -1); len(a) != len(b) {
+				t.Errorf("%s: %s has verbs %v, English has %v", lang, key, a, b)
+			} else {
+				for i := range a {
+					if a[i] != b[i] {
+						t.Errorf("%s: %s has verbs %v, English has %v", lang, key, a, b)
+						break
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	catalog["test"] = map[string]string{"inspect.name": "Nom : %s"}
+	defer delete(catalog, "test")
+
+	cases := []struct {
+		lang, key string
+		args      []any
+		expected  string
+	}{
+		{lang: "test", key: "inspect.name", args: []any{"pikachu"}, expected: "Nom : pikachu"},
+		{lang: "test", key: "inspect.level", args: []any{5}, expected: "Level: 5"},
+		{lang: "xx", key: "stats", expected: "Stats:"},
+		{lang: "en", key: "no.such.key", expected: "no.such.key"},
+	}
+	for _, c := range cases {
+		if actual := T(c.lang, c.key, c.args...); actual != c.expected {
+			t.Errorf("T(%s, %s): expected %q, got %q", c.lang, c.key, c.expected, actual)
+		}
+	}
+}
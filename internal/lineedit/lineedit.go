@@ -0,0 +1,444 @@
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode"
+)
+
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the candidates for word, the text between the last
+// space and the cursor. head is everything on the line before word.
+type Completer func(head, word string) []string
+
+type Editor struct {
+	Prompt   string
+	Complete Completer
+	History  []string
+
+	in       *bufio.Reader
+	out      io.Writer
+	fd       uintptr
+	terminal bool
+	// interactive is set when a person is typing, even on a terminal that
+	// can't be put in raw mode, so the prompt is still shown.
+	interactive bool
+}
+
+type key int
+
+const (
+	keyRune key = iota
+	keyEnter
+	keyBackspace
+	keyDelete
+	keyLeft
+	keyRight
+	keyUp
+	keyDown
+	keyHome
+	keyEnd
+	keyTab
+	keyCtrlC
+	keyCtrlD
+	keyCtrlG
+	keyCtrlK
+	keyCtrlL
+	keyCtrlR
+	keyCtrlU
+	keyCtrlW
+	keyEscape
+	keyUnknown
+)
+
+type state struct {
+	buf []rune
+	pos int
+	// hist is the history entry being shown; len(History) is the line
+	// being typed, which is kept in pending while browsing.
+	hist    int
+	pending []rune
+}
+
+func New(in *os.File, out io.Writer) *Editor {
+	terminal := isTerminal(in.Fd())
+	info, err := in.Stat()
+	return &Editor{
+		in:          bufio.NewReader(in),
+		out:         out,
+		fd:          in.Fd(),
+		terminal:    terminal,
+		interactive: terminal || (err == nil && info.Mode()&os.ModeCharDevice != 0),
+	}
+}
+
+// Interactive reports whether a person is typing the input, so it makes
+// sense to prompt for an answer.
+func (e *Editor) Interactive() bool {
+	return e.interactive
+}
+
+// AddHistory records a line, skipping blank lines and immediate repeats.
+func (e *Editor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" {
+		return
+	}
+	if n := len(e.History); n > 0 && e.History[n-1] == line {
+		return
+	}
+	e.History = append(e.History, line)
+}
+
+// ReadLine prints the prompt and reads one line. On a terminal the line can
+// be edited; otherwise it is read as-is, and without a prompt unless the
+// input is interactive, so piped output stays clean. It returns io.EOF on
+// end of input and ErrInterrupted when the user presses Ctrl-C.
+func (e *Editor) ReadLine() (string, error) {
+	if e.terminal {
+		if restore, err := makeRaw(e.fd); err == nil {
+			defer restore()
+			return e.edit()
+		}
+	}
+	if e.interactive {
+		fmt.Fprint(e.out, e.Prompt)
+	}
+	line, err := e.in.ReadString('\n')
+	if err == io.EOF && line != "" {
+		err = nil
+	}
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+func (e *Editor) readKey() (key, rune, error) {
+	r, _, err := e.in.ReadRune()
+	if err != nil {
+		return keyUnknown, 0, err
+	}
+	switch r {
+	case '\r', '\n':
+		return keyEnter, r, nil
+	case 127, 8:
+		return keyBackspace, r, nil
+	case '\t':
+		return keyTab, r, nil
+	case 1:
+		return keyHome, r, nil
+	case 2:
+		return keyLeft, r, nil
+	case 3:
+		return keyCtrlC, r, nil
+	case 4:
+		return keyCtrlD, r, nil
+	case 5:
+		return keyEnd, r, nil
+	case 6:
+		return keyRight, r, nil
+	case 7:
+		return keyCtrlG, r, nil
+	case 11:
+		return keyCtrlK, r, nil
+	case 12:
+		return keyCtrlL, r, nil
+	case 14:
+		return keyDown, r, nil
+	case 16:
+		return keyUp, r, nil
+	case 18:
+		return keyCtrlR, r, nil
+	case 21:
+		return keyCtrlU, r, nil
+	case 23:
+		return keyCtrlW, r, nil
+	case 27:
+		return e.readEscape()
+	}
+	if unicode.IsControl(r) {
+		return keyUnknown, r, nil
+	}
+	return keyRune, r, nil
+}
+
+// readEscape decodes the CSI and SS3 sequences terminals send for the arrow,
+// home, end and delete keys. A lone escape press has nothing buffered after
+// it, which is how it is told apart from a sequence.
+func (e *Editor) readEscape() (key, rune, error) {
+	if e.in.Buffered() == 0 {
+		return keyEscape, 27, nil
+	}
+	intro, _, err := e.in.ReadRune()
+	if err != nil {
+		return keyUnknown, 0, err
+	}
+	if intro != '[' && intro != 'O' {
+		return keyUnknown, intro, nil
+	}
+	var params []rune
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return keyUnknown, 0, err
+		}
+		if r >= '0' && r <= '9' || r == ';' {
+			params = append(params, r)
+			continue
+		}
+		switch r {
+		case 'A':
+			return keyUp, r, nil
+		case 'B':
+			return keyDown, r, nil
+		case 'C':
+			return keyRight, r, nil
+		case 'D':
+			return keyLeft, r, nil
+		case 'H':
+			return keyHome, r, nil
+		case 'F':
+			return keyEnd, r, nil
+		case '~':
+			switch string(params) {
+			case "1", "7":
+				return keyHome, r, nil
+			case "4", "8":
+				return keyEnd, r, nil
+			case "3":
+				return keyDelete, r, nil
+			}
+		}
+		return keyUnknown, r, nil
+	}
+}
+
+func (e *Editor) redraw(st *state) {
+	fmt.Fprintf(e.out, "\r%s%s\x1b[K", e.Prompt, string(st.buf))
+	if back := len(st.buf) - st.pos; back > 0 {
+		fmt.Fprintf(e.out, "\x1b[%dD", back)
+	}
+}
+
+func (st *state) set(line []rune) {
+	st.buf = append([]rune(nil), line...)
+	st.pos = len(st.buf)
+}
+
+func (st *state) insert(runes ...rune) {
+	buf := make([]rune, 0, len(st.buf)+len(runes))
+	buf = append(buf, st.buf[:st.pos]...)
+	buf = append(buf, runes...)
+	buf = append(buf, st.buf[st.pos:]...)
+	st.buf = buf
+	st.pos += len(runes)
+}
+
+func (st *state) wordStart() int {
+	start := st.pos
+	for start > 0 && st.buf[start-1] != ' ' {
+		start--
+	}
+	return start
+}
+
+func (e *Editor) edit() (string, error) {
+	st := &state{hist: len(e.History)}
+	e.redraw(st)
+	var k key
+	var r rune
+	var err error
+	replay := false
+	for {
+		if !replay {
+			k, r, err = e.readKey()
+			if err != nil {
+				return "", err
+			}
+		}
+		replay = false
+		switch k {
+		case keyRune:
+			st.insert(r)
+		case keyEnter:
+			fmt.Fprint(e.out, "\r\n")
+			return string(st.buf), nil
+		case keyBackspace:
+			if st.pos > 0 {
+				st.buf = append(st.buf[:st.pos-1], st.buf[st.pos:]...)
+				st.pos--
+			}
+		case keyDelete:
+			if st.pos < len(st.buf) {
+				st.buf = append(st.buf[:st.pos], st.buf[st.pos+1:]...)
+			}
+		case keyLeft:
+			if st.pos > 0 {
+				st.pos--
+			}
+		case keyRight:
+			if st.pos < len(st.buf) {
+				st.pos++
+			}
+		case keyHome:
+			st.pos = 0
+		case keyEnd:
+			st.pos = len(st.buf)
+		case keyUp:
+			if st.hist > 0 {
+				if st.hist == len(e.History) {
+					st.pending = append([]rune(nil), st.buf...)
+				}
+				st.hist--
+				st.set([]rune(e.History[st.hist]))
+			}
+		case keyDown:
+			if st.hist < len(e.History) {
+				st.hist++
+				if st.hist == len(e.History) {
+					st.set(st.pending)
+				} else {
+					st.set([]rune(e.History[st.hist]))
+				}
+			}
+		case keyTab:
+			e.complete(st)
+		case keyCtrlC:
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(st.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			if st.pos < len(st.buf) {
+				st.buf = append(st.buf[:st.pos], st.buf[st.pos+1:]...)
+			}
+		case keyCtrlK:
+			st.buf = st.buf[:st.pos]
+		case keyCtrlU:
+			st.buf = append([]rune(nil), st.buf[st.pos:]...)
+			st.pos = 0
+		case keyCtrlW:
+			end := st.pos
+			for st.pos > 0 && st.buf[st.pos-1] == ' ' {
+				st.pos--
+			}
+			st.pos = st.wordStart()
+			st.buf = append(st.buf[:st.pos], st.buf[end:]...)
+		case keyCtrlL:
+			fmt.Fprint(e.out, "\x1b[H\x1b[2J")
+		case keyCtrlR:
+			k, r, err = e.reverseSearch(st)
+			if err != nil {
+				return "", err
+			}
+			if k == keyEnter {
+				fmt.Fprint(e.out, "\r\n")
+				return string(st.buf), nil
+			}
+			// The key that ended the search still applies to the line.
+			replay = k != keyUnknown
+			if replay {
+				continue
+			}
+		}
+		e.redraw(st)
+	}
+}
+
+func (e *Editor) complete(st *state) {
+	if e.Complete == nil {
+		return
+	}
+	start := st.wordStart()
+	word := string(st.buf[start:st.pos])
+	candidates := e.Complete(string(st.buf[:start]), word)
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		st.insert([]rune(strings.TrimPrefix(candidates[0], word) + " ")...)
+		return
+	}
+	if prefix := commonPrefix(candidates); len(prefix) > len(word) && strings.HasPrefix(prefix, word) {
+		st.insert([]rune(prefix[len(word):])...)
+		return
+	}
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// reverseSearch implements Ctrl-R incremental search through history. It
+// leaves the match in the line and returns the key that ended the search:
+// keyEnter to submit, keyUnknown when cancelled, or any other key for the
+// editor to apply.
+func (e *Editor) reverseSearch(st *state) (key, rune, error) {
+	original := append([]rune(nil), st.buf...)
+	var query []rune
+	match := -1
+	find := func(from int) {
+		for i := from; i >= 0; i-- {
+			if strings.Contains(e.History[i], string(query)) {
+				match = i
+				return
+			}
+		}
+	}
+	for {
+		found := ""
+		if match >= 0 {
+			found = e.History[match]
+		}
+		fmt.Fprintf(e.out, "\r(reverse-i-search)`%s': %s\x1b[K", string(query), found)
+
+		k, r, err := e.readKey()
+		if err != nil {
+			return keyUnknown, 0, err
+		}
+		switch k {
+		case keyRune:
+			query = append(query, r)
+			from := len(e.History) - 1
+			if match >= 0 {
+				from = match
+			}
+			match = -1
+			find(from)
+		case keyBackspace:
+			if len(query) > 0 {
+				query = query[:len(query)-1]
+			}
+			match = -1
+			find(len(e.History) - 1)
+		case keyCtrlR:
+			if match > 0 {
+				previous := match
+				find(match - 1)
+				if match < 0 {
+					match = previous
+				}
+			}
+		case keyCtrlG, keyCtrlC:
+			st.set(original)
+			return keyUnknown, 0, nil
+		default:
+			if match >= 0 {
+				st.set([]rune(e.History[match]))
+				st.hist = match
+			}
+			return k, r, nil
+		}
+	}
+}
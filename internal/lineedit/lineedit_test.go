@@ -0,0 +1,157 @@
+package lineedit
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func newTestEditor(input string, history ...string) (*Editor, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	return &Editor{
+		Prompt:  "> ",
+		History: history,
+		in:      bufio.NewReader(strings.NewReader(input)),
+		out:     out,
+	}, out
+}
+
+func TestEdit(t *testing.T) {
+	cases := []struct {
+		name     string
+		input    string
+		history  []string
+		expected string
+	}{
+		{name: "plain", input: "map\r", expected: "map"},
+		{name: "backspace", input: "mapp\x7f\r", expected: "map"},
+		{name: "insert after moving left", input: "ctch\x1b[D\x1b[D\x1b[Da\r", expected: "catch"},
+		{name: "home and end", input: "atch\x01c\x05 x\r", expected: "catch x"},
+		{name: "delete", input: "mapb\x1b[D\x1b[3~\r", expected: "map"},
+		{name: "kill to start", input: "junk help\x1b[D\x1b[D\x1b[D\x1b[D\x15\r", expected: "help"},
+		{name: "kill to end", input: "help junk\x01\x1b[C\x1b[C\x1b[C\x1b[C\x0b\r", expected: "help"},
+		{name: "delete word", input: "catch pikachu\x17\r", expected: "catch "},
+		{name: "history up", input: "\x1b[A\x1b[A\r", history: []string{"map", "explore eterna-city-area"}, expected: "map"},
+		{name: "history down restores typing", input: "ca\x1b[A\x1b[B\r", history: []string{"map"}, expected: "ca"},
+		{name: "reverse search submits", input: "\x12ete\r", history: []string{"explore eterna-city-area", "map", "help"}, expected: "explore eterna-city-area"},
+		{name: "reverse search older match", input: "\x12ca\x12\r", history: []string{"catch pichu", "map", "catch pikachu"}, expected: "catch pichu"},
+		{name: "reverse search then edit", input: "\x12ma\x1b[Db\r", history: []string{"map"}, expected: "mabp"},
+		{name: "reverse search cancelled", input: "he\x12ma\x07lp\r", history: []string{"map"}, expected: "help"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, _ := newTestEditor(c.input, c.history...)
+			actual, err := e.edit()
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if actual != c.expected {
+				t.Errorf("expected %q, got %q", c.expected, actual)
+			}
+		})
+	}
+}
+
+func TestEditControl(t *testing.T) {
+	e, _ := newTestEditor("\x04")
+	if _, err := e.edit(); err != io.EOF {
+		t.Errorf("expected io.EOF on Ctrl-D, got %v", err)
+	}
+	e, _ = newTestEditor("map\x03")
+	if _, err := e.edit(); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted on Ctrl-C, got %v", err)
+	}
+}
+
+func TestComplete(t *testing.T) {
+	words := []string{"explore", "exit", "evolve", "evolutions"}
+	completer := func(head, word string) []string {
+		if head != "" {
+			if head == "explore " {
+				return []string{"eterna-city-area"}
+			}
+			return nil
+		}
+		var out []string
+		for _, w := range words {
+			if strings.HasPrefix(w, word) {
+				out = append(out, w)
+			}
+		}
+		return out
+	}
+
+	cases := []struct {
+		input    string
+		expected string
+	}{
+		{input: "expl\t\r", expected: "explore "},
+		{input: "ev\t\r", expected: "evol"},
+		{input: "explore et\t\r", expected: "explore eterna-city-area "},
+		{input: "zz\t\r", expected: "zz"},
+	}
+	for _, c := range cases {
+		e, _ := newTestEditor(c.input)
+		e.Complete = completer
+		actual, err := e.edit()
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if actual != c.expected {
+			t.Errorf("%q: expected %q, got %q", c.input, c.expected, actual)
+		}
+	}
+
+	e, out := newTestEditor("e\t\r")
+	e.Complete = completer
+	if _, err := e.edit(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "explore  exit  evolve  evolutions") {
+		t.Errorf("expected the candidates to be listed, got %q", out.String())
+	}
+}
+
+func TestAddHistory(t *testing.T) {
+	e, _ := newTestEditor("")
+	for _, line := range []string{"map", "map", " ", "help", "map"} {
+		e.AddHistory(line)
+	}
+	if strings.Join(e.History, ",") != "map,help,map" {
+		t.Errorf("unexpected history %v", e.History)
+	}
+}
+
+func TestReadLineNotTerminal(t *testing.T) {
+	e, out := newTestEditor("explore eterna-city-area\nmap")
+	line, err := e.ReadLine()
+	if err != nil || line != "explore eterna-city-area" {
+		t.Errorf("expected first line, got %q %v", line, err)
+	}
+	line, err = e.ReadLine()
+	if err != nil || line != "map" {
+		t.Errorf("expected unterminated last line, got %q %v", line, err)
+	}
+	if _, err = e.ReadLine(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+	if out.String() != "" {
+		t.Errorf("expected no prompt when reading from a pipe, got %q", out.String())
+	}
+}
+
+func TestReadLineInteractiveWithoutRawMode(t *testing.T) {
+	e, out := newTestEditor("map\n")
+	e.Prompt = "Pokedex > "
+	e.interactive = true
+	line, err := e.ReadLine()
+	if err != nil || line != "map" {
+		t.Errorf("expected the line, got %q %v", line, err)
+	}
+	if out.String() != "Pokedex > " {
+		t.Errorf("expected the prompt on interactive input, got %q", out.String())
+	}
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package lineedit
+
+import "syscall"
+
+const (
+	ioctlReadTermios  = syscall.TCGETS
+	ioctlWriteTermios = syscall.TCSETS
+)
@@ -0,0 +1,56 @@
+//go:build windows
+
+package lineedit
+
+import "syscall"
+
+const (
+	enableProcessedInput        = 0x1
+	enableLineInput             = 0x2
+	enableEchoInput             = 0x4
+	enableVirtualTerminalInput  = 0x200
+	enableVirtualTerminalOutput = 0x4
+)
+
+var setConsoleMode = syscall.NewLazyDLL("kernel32.dll").NewProc("SetConsoleMode")
+
+func setMode(h syscall.Handle, mode uint32) error {
+	if ok, _, err := setConsoleMode.Call(uintptr(h), uintptr(mode)); ok == 0 {
+		return err
+	}
+	return nil
+}
+
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+// makeRaw turns off line input, echo and Ctrl-C handling on the console and
+// asks for the same escape sequences a Unix terminal sends, on input and on
+// output, returning a function that restores both modes.
+func makeRaw(fd uintptr) (func(), error) {
+	in := syscall.Handle(fd)
+	var oldIn uint32
+	if err := syscall.GetConsoleMode(in, &oldIn); err != nil {
+		return nil, err
+	}
+	raw := oldIn&^(enableProcessedInput|enableLineInput|enableEchoInput) | enableVirtualTerminalInput
+	if err := setMode(in, raw); err != nil {
+		return nil, err
+	}
+	out, _ := syscall.GetStdHandle(syscall.STD_OUTPUT_HANDLE)
+	var oldOut uint32
+	outErr := syscall.GetConsoleMode(out, &oldOut)
+	if outErr == nil {
+		outErr = setMode(out, oldOut|enableVirtualTerminalOutput)
+	}
+	if outErr != nil {
+		setMode(in, oldIn)
+		return nil, outErr
+	}
+	return func() {
+		setMode(in, oldIn)
+		setMode(out, oldOut)
+	}, nil
+}
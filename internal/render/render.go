@@ -0,0 +1,267 @@
+// Package render draws tables, bar charts and type badges for the terminal.
+// Everything is plain text unless the Style enables color.
+package render
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const defaultWidth = 80
+
+type Style struct {
+	Color bool
+	// TrueColor is set when the terminal advertises 24-bit color.
+	TrueColor bool
+	// Width is the terminal width in columns; zero means the default of 80.
+	Width int
+}
+
+// Detect works out the style for output written to f: color only on a
+// terminal and only when NO_COLOR is unset and TERM isn't "dumb", 24-bit
+// color when $COLORTERM says so, and width from the terminal, then $COLUMNS.
+func Detect(f *os.File) Style {
+	tty := isTerminal(f.Fd())
+	style := Style{
+		Color: tty && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb",
+	}
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		style.TrueColor = style.Color
+	}
+	if tty {
+		style.Width = terminalWidth(f.Fd())
+	}
+	if style.Width == 0 {
+		if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+			style.Width = n
+		}
+	}
+	return style
+}
+
+func (s Style) width() int {
+	if s.Width <= 0 {
+		return defaultWidth
+	}
+	return s.Width
+}
+
+// paint wraps text in an SGR escape sequence when color is enabled.
+func (s Style) paint(sgr, text string) string {
+	if !s.Color || sgr == "" {
+		return text
+	}
+	return "\x1b[" + sgr + "m" + text + "\x1b[0m"
+}
+
+func (s Style) Bold(text string) string {
+	return s.paint("1", text)
+}
+
+func (s Style) Dim(text string) string {
+	return s.paint("2", text)
+}
+
+// VisibleWidth is the number of columns text takes up, ignoring escape
+// sequences.
+func VisibleWidth(text string) int {
+	n := 0
+	for i := 0; i < len(text); {
+		if text[i] == '\x1b' {
+			end := strings.IndexByte(text[i:], 'm')
+			if end < 0 {
+				break
+			}
+			i += end + 1
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		n++
+	}
+	return n
+}
+
+func pad(text string, width int, right bool) string {
+	gap := width - VisibleWidth(text)
+	if gap <= 0 {
+		return text
+	}
+	if right {
+		return strings.Repeat(" ", gap) + text
+	}
+	return text + strings.Repeat(" ", gap)
+}
+
+// truncate shortens plain text to width columns, marking the cut with an
+// ellipsis. Text containing escape sequences is left alone.
+func truncate(text string, width int) string {
+	if VisibleWidth(text) <= width || strings.Contains(text, "\x1b") || width < 1 {
+		return text
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
+
+type Table struct {
+	Headers []string
+	// Right lists the columns to right-align, such as numbers.
+	Right []bool
+	Rows  [][]string
+}
+
+func (t *Table) Append(cells ...string) {
+	t.Rows = append(t.Rows, cells)
+}
+
+// Render writes the table with columns padded to their widest cell. If it
+// would be wider than the terminal, the last column is cut short.
+func (t *Table) Render(w io.Writer, style Style) error {
+	columns := len(t.Headers)
+	widths := make([]int, columns)
+	for _, row := range append([][]string{t.Headers}, t.Rows...) {
+		for i := 0; i < columns && i < len(row); i++ {
+			widths[i] = max(widths[i], VisibleWidth(row[i]))
+		}
+	}
+	total := 2 * (columns - 1)
+	for _, width := range widths {
+		total += width
+	}
+	if over := total - style.width(); over > 0 && columns > 0 {
+		widths[columns-1] = max(widths[columns-1]-over, 4)
+	}
+
+	line := func(row []string, header bool) error {
+		cells := make([]string, columns)
+		for i := range cells {
+			cell := ""
+			if i < len(row) {
+				cell = truncate(row[i], widths[i])
+			}
+			right := i < len(t.Right) && t.Right[i]
+			if i == columns-1 && !right {
+				cells[i] = cell
+			} else {
+				cells[i] = pad(cell, widths[i], right)
+			}
+			if header {
+				cells[i] = style.Bold(cells[i])
+			}
+		}
+		_, err := fmt.Fprintln(w, strings.TrimRight(strings.Join(cells, "  "), " "))
+		return err
+	}
+	if err := line(t.Headers, true); err != nil {
+		return err
+	}
+	for _, row := range t.Rows {
+		if err := line(row, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var barEighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// Bar draws value as a horizontal bar out of limit, width columns wide at
+// most, using eighth blocks for the remainder. With color it is tinted from
+// red for low values to green for high ones.
+func Bar(value, limit, width int, style Style) string {
+	if limit <= 0 || width <= 0 {
+		return ""
+	}
+	value = min(max(value, 0), limit)
+	eighths := value * width * 8 / limit
+	bar := strings.Repeat("█", eighths/8) + barEighths[eighths%8]
+	if bar == "" && value > 0 {
+		bar = barEighths[1]
+	}
+	return style.paint(barColor(value*100/limit), bar)
+}
+
+func barColor(percent int) string {
+	switch {
+	case percent < 20:
+		return "31"
+	case percent < 35:
+		return "33"
+	case percent < 50:
+		return "32"
+	default:
+		return "36"
+	}
+}
+
+var typeColors = map[string][3]int{
+	"normal":   {0xA8, 0xA7, 0x7A},
+	"fire":     {0xEE, 0x81, 0x30},
+	"water":    {0x63, 0x90, 0xF0},
+	"electric": {0xF7, 0xD0, 0x2C},
+	"grass":    {0x7A, 0xC7, 0x4C},
+	"ice":      {0x96, 0xD9, 0xD6},
+	"fighting": {0xC2, 0x2E, 0x28},
+	"poison":   {0xA3, 0x3E, 0xA1},
+	"ground":   {0xE2, 0xBF, 0x65},
+	"flying":   {0xA9, 0x8F, 0xF3},
+	"psychic":  {0xF9, 0x55, 0x87},
+	"bug":      {0xA6, 0xB9, 0x1A},
+	"rock":     {0xB6, 0xA1, 0x36},
+	"ghost":    {0x73, 0x57, 0x97},
+	"dragon":   {0x6F, 0x35, 0xFC},
+	"dark":     {0x70, 0x57, 0x46},
+	"steel":    {0xB7, 0xB7, 0xCE},
+	"fairy":    {0xD6, 0x85, 0xAD},
+}
+
+// Badge shows a type name on its usual color, with dark or light text
+// depending on how bright that color is. Without 24-bit color the nearest
+// 256-color entry is used. Unknown types and plain output get the bare name.
+func Badge(typeName string, style Style) string {
+	rgb, ok := typeColors[typeName]
+	if !ok || !style.Color {
+		return typeName
+	}
+	fg := "97"
+	if rgb[0]*299+rgb[1]*587+rgb[2]*114 > 150000 {
+		fg = "30"
+	}
+	bg := fmt.Sprintf("48;5;%d", xterm256(rgb))
+	if style.TrueColor {
+		bg = fmt.Sprintf("48;2;%d;%d;%d", rgb[0], rgb[1], rgb[2])
+	}
+	return style.paint("1;"+fg+";"+bg, " "+typeName+" ")
+}
+
+// xterm256 maps a color to the nearest entry in the 6x6x6 color cube of the
+// 256-color palette.
+func xterm256(rgb [3]int) int {
+	level := func(v int) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	return 16 + 36*level(rgb[0]) + 6*level(rgb[1]) + level(rgb[2])
+}
+
+func Badges(typeNames []string, style Style) string {
+	badges := make([]string, len(typeNames))
+	for i, name := range typeNames {
+		badges[i] = Badge(name, style)
+	}
+	separator := " "
+	if !style.Color {
+		separator = "/"
+	}
+	return strings.Join(badges, separator)
+}
@@ -0,0 +1,69 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	table := Table{Headers: []string{"#", "Name", "Types"}, Right: []bool{true}}
+	table.Append("25", "pikachu", "electric")
+	table.Append("6", "charizard", "fire/flying")
+	var b strings.Builder
+	if err := table.Render(&b, Style{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := " #  Name       Types\n" +
+		"25  pikachu    electric\n" +
+		" 6  charizard  fire/flying\n"
+	if b.String() != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, b.String())
+	}
+
+	b.Reset()
+	if err := table.Render(&b, Style{Width: 20}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		if VisibleWidth(line) > 20 {
+			t.Errorf("Expected lines to fit in 20 columns, got %q", line)
+		}
+	}
+}
+
+func TestBar(t *testing.T) {
+	cases := []struct {
+		value, limit, width int
+		expected            string
+	}{
+		{value: 100, limit: 100, width: 4, expected: "████"},
+		{value: 50, limit: 100, width: 4, expected: "██"},
+		{value: 35, limit: 255, width: 10, expected: "█▎"},
+		{value: 1, limit: 255, width: 10, expected: "▏"},
+		{value: 0, limit: 255, width: 10, expected: ""},
+	}
+	for _, c := range cases {
+		if actual := Bar(c.value, c.limit, c.width, Style{}); actual != c.expected {
+			t.Errorf("Bar(%d, %d, %d): expected %q, got %q", c.value, c.limit, c.width, c.expected, actual)
+		}
+	}
+	if colored := Bar(200, 255, 10, Style{Color: true}); !strings.HasPrefix(colored, "\x1b[") || VisibleWidth(colored) != 8 {
+		t.Errorf("Expected a colored bar 8 columns wide, got %q", colored)
+	}
+}
+
+func TestBadges(t *testing.T) {
+	if plain := Badges([]string{"fire", "flying"}, Style{}); plain != "fire/flying" {
+		t.Errorf("Expected plain badges, got %q", plain)
+	}
+	colored := Badge("electric", Style{Color: true, TrueColor: true})
+	if !strings.HasPrefix(colored, "\x1b[1;30;48;2;247;208;44m") {
+		t.Errorf("Expected dark text on yellow, got %q", colored)
+	}
+	if limited := Badge("electric", Style{Color: true}); !strings.HasPrefix(limited, "\x1b[1;30;48;5;220m") {
+		t.Errorf("Expected the 256-color yellow without 24-bit color, got %q", limited)
+	}
+	if VisibleWidth(colored) != len(" electric ") {
+		t.Errorf("Expected escape codes to take no columns, got %d", VisibleWidth(colored))
+	}
+}
@@ -0,0 +1,7 @@
+//go:build darwin || dragonfly || freebsd || netbsd || openbsd
+
+package render
+
+import "syscall"
+
+const ioctlReadTermios = syscall.TIOCGETA
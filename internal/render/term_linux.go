@@ -0,0 +1,7 @@
+//go:build linux
+
+package render
+
+import "syscall"
+
+const ioctlReadTermios = syscall.TCGETS
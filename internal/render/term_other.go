@@ -0,0 +1,11 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd && !windows
+
+package render
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+func terminalWidth(fd uintptr) int {
+	return 0
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package render
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const enableVirtualTerminalProcessing = 0x4
+
+var (
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	setConsoleMode             = kernel32.NewProc("SetConsoleMode")
+	getConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// isTerminal reports whether fd is a console that understands escape
+// sequences, switching that on when the console supports it.
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	if syscall.GetConsoleMode(syscall.Handle(fd), &mode) != nil {
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	ok, _, _ := setConsoleMode.Call(fd, uintptr(mode|enableVirtualTerminalProcessing))
+	return ok != 0
+}
+
+func terminalWidth(fd uintptr) int {
+	var info struct {
+		size, cursor                   struct{ x, y int16 }
+		attributes                     uint16
+		left, top, right, bottom       int16
+		maxWindowSizeX, maxWindowSizeY int16
+	}
+	if ok, _, _ := getConsoleScreenBufferInfo.Call(fd, uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0
+	}
+	return int(info.right-info.left) + 1
+}
@@ -0,0 +1,191 @@
+// Package sprite turns Pokemon sprite images into text for the terminal,
+// either as colored half-block characters, two pixels per cell, or as ASCII
+// art for terminals without color.
+package sprite
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	_ "image/png"
+	"strings"
+)
+
+// Mode selects how pixels are drawn.
+type Mode int
+
+const (
+	ASCII Mode = iota
+	// Color256 uses the xterm 256-color palette.
+	Color256
+	// TrueColor uses 24-bit color.
+	TrueColor
+)
+
+type Options struct {
+	Mode Mode
+	// Width is the most columns the sprite may take; zero means no limit.
+	Width int
+}
+
+const alphaThreshold = 128
+
+// asciiRamp runs from the lightest to the darkest character.
+const asciiRamp = " .:-=+*#%@"
+
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decoding sprite: %w", err)
+	}
+	return img, nil
+}
+
+func opaque(c color.Color) bool {
+	_, _, _, a := c.RGBA()
+	return a>>8 >= alphaThreshold
+}
+
+// Crop trims the fully transparent border most sprites have.
+func Crop(img image.Image) image.Image {
+	b := img.Bounds()
+	minX, minY, maxX, maxY := b.Max.X, b.Max.Y, b.Min.X-1, b.Min.Y-1
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if opaque(img.At(x, y)) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < minX {
+		return img
+	}
+	return subImage{img, image.Rect(minX, minY, maxX+1, maxY+1)}
+}
+
+type subImage struct {
+	image.Image
+	rect image.Rectangle
+}
+
+func (s subImage) Bounds() image.Rectangle {
+	return s.rect
+}
+
+// scaled samples img with nearest-neighbour scaling by an integer factor.
+type scaled struct {
+	image.Image
+	factor int
+}
+
+func (s scaled) Bounds() image.Rectangle {
+	b := s.Image.Bounds()
+	return image.Rect(0, 0, (b.Dx()+s.factor-1)/s.factor, (b.Dy()+s.factor-1)/s.factor)
+}
+
+func (s scaled) At(x, y int) color.Color {
+	b := s.Image.Bounds()
+	return s.Image.At(b.Min.X+x*s.factor, b.Min.Y+y*s.factor)
+}
+
+// Render crops img, shrinks it to fit opts.Width and draws it as text.
+func Render(img image.Image, opts Options) string {
+	img = Crop(img)
+	if opts.Width > 0 && img.Bounds().Dx() > opts.Width {
+		factor := (img.Bounds().Dx() + opts.Width - 1) / opts.Width
+		img = scaled{img, factor}
+	}
+	if opts.Mode == ASCII {
+		return renderASCII(img)
+	}
+	return renderHalfBlocks(img, opts.Mode)
+}
+
+// renderHalfBlocks draws two pixel rows per line with "▀", coloring its
+// foreground with the upper pixel and its background with the lower one.
+func renderHalfBlocks(img image.Image, mode Mode) string {
+	var b strings.Builder
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			top := img.At(x, y)
+			var bottom color.Color = color.Transparent
+			if y+1 < bounds.Max.Y {
+				bottom = img.At(x, y+1)
+			}
+			switch {
+			case opaque(top) && opaque(bottom):
+				b.WriteString("\x1b[" + sgr(top, mode, 38) + ";" + sgr(bottom, mode, 48) + "m▀")
+			case opaque(top):
+				b.WriteString("\x1b[0;" + sgr(top, mode, 38) + "m▀")
+			case opaque(bottom):
+				b.WriteString("\x1b[0;" + sgr(bottom, mode, 38) + "m▄")
+			default:
+				b.WriteString("\x1b[0m ")
+			}
+		}
+		b.WriteString("\x1b[0m\n")
+	}
+	return b.String()
+}
+
+// sgr is the escape parameter setting the foreground (base 38) or
+// background (base 48) to c.
+func sgr(c color.Color, mode Mode, base int) string {
+	r, g, bl, _ := c.RGBA()
+	r, g, bl = r>>8, g>>8, bl>>8
+	if mode == TrueColor {
+		return fmt.Sprintf("%d;2;%d;%d;%d", base, r, g, bl)
+	}
+	return fmt.Sprintf("%d;5;%d", base, xterm256(r, g, bl))
+}
+
+// xterm256 maps a color to the nearest entry in the 6x6x6 color cube of the
+// 256-color palette.
+func xterm256(r, g, b uint32) int {
+	level := func(v uint32) int {
+		if v < 48 {
+			return 0
+		}
+		if v < 115 {
+			return 1
+		}
+		return int((v - 35) / 40)
+	}
+	return 16 + 36*level(r) + 6*level(g) + level(b)
+}
+
+// renderASCII draws one character per two pixel rows, since character
+// cells are about twice as tall as they are wide.
+func renderASCII(img image.Image) string {
+	var b strings.Builder
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += 2 {
+		var line strings.Builder
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var total, count uint32
+			for dy := 0; dy < 2 && y+dy < bounds.Max.Y; dy++ {
+				c := img.At(x, y+dy)
+				if !opaque(c) {
+					continue
+				}
+				r, g, bl, _ := c.RGBA()
+				total += (299*(r>>8) + 587*(g>>8) + 114*(bl>>8)) / 1000
+				count++
+			}
+			if count == 0 {
+				line.WriteByte(' ')
+				continue
+			}
+			darkness := 255 - total/count
+			// Opaque pixels always get a visible character, even white ones.
+			index := 1 + int(darkness)*(len(asciiRamp)-1)/256
+			line.WriteByte(asciiRamp[index])
+		}
+		b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+	return b.String()
+}
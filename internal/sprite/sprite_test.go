@@ -0,0 +1,57 @@
+package sprite
+
+import (
+	"image"
+	"os"
+	"strings"
+	"testing"
+)
+
+func loadFixture(t *testing.T, name string) image.Image {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return img
+}
+
+func TestCrop(t *testing.T) {
+	img := Crop(loadFixture(t, "flag.png"))
+	if b := img.Bounds(); b != image.Rect(1, 2, 7, 6) {
+		t.Errorf("Expected the transparent border to be cropped, got %v", b)
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	img := loadFixture(t, "flag.png")
+	if actual := Render(img, Options{Mode: ASCII}); actual != "######\n@@@...\n" {
+		t.Errorf("Unexpected ASCII art:\n%s", actual)
+	}
+	if actual := Render(img, Options{Mode: ASCII, Width: 3}); actual != "%%=\n" {
+		t.Errorf("Expected the sprite scaled to 3 columns, got:\n%s", actual)
+	}
+}
+
+func TestRenderHalfBlocks(t *testing.T) {
+	img := loadFixture(t, "blue.png")
+	both := "\x1b[38;2;0;0;255;48;2;0;0;255m▀"
+	expected := strings.Repeat(both, 3) + "\x1b[0;38;2;0;0;255m▄\x1b[0m\n" +
+		strings.Repeat("\x1b[0;38;2;0;0;255m▀", 4) + "\x1b[0m\n"
+	if actual := Render(img, Options{Mode: TrueColor}); actual != expected {
+		t.Errorf("Expected %q, got %q", expected, actual)
+	}
+	if actual := Render(img, Options{Mode: Color256}); !strings.HasPrefix(actual, "\x1b[38;5;21;48;5;21m▀") {
+		t.Errorf("Expected 256-color blue, got %q", actual)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	if _, err := Decode([]byte("not an image")); err == nil {
+		t.Errorf("Expected an error for invalid data")
+	}
+}
@@ -0,0 +1,69 @@
+package stats
+
+import "math/rand"
+
+type Nature struct {
+	Name      string
+	Increased Stat
+	Decreased Stat
+}
+
+func (n Nature) Neutral() bool {
+	return n.Increased == n.Decreased
+}
+
+// Modifier returns the nature multiplier for s as a percentage so callers
+// can stay in integer arithmetic like the games do.
+func (n Nature) Modifier(s Stat) int {
+	switch {
+	case n.Neutral():
+		return 100
+	case s == n.Increased:
+		return 110
+	case s == n.Decreased:
+		return 90
+	default:
+		return 100
+	}
+}
+
+var Natures = []Nature{
+	{"hardy", Attack, Attack},
+	{"lonely", Attack, Defense},
+	{"brave", Attack, Speed},
+	{"adamant", Attack, SpAttack},
+	{"naughty", Attack, SpDefense},
+	{"bold", Defense, Attack},
+	{"docile", Defense, Defense},
+	{"relaxed", Defense, Speed},
+	{"impish", Defense, SpAttack},
+	{"lax", Defense, SpDefense},
+	{"timid", Speed, Attack},
+	{"hasty", Speed, Defense},
+	{"serious", Speed, Speed},
+	{"jolly", Speed, SpAttack},
+	{"naive", Speed, SpDefense},
+	{"modest", SpAttack, Attack},
+	{"mild", SpAttack, Defense},
+	{"quiet", SpAttack, Speed},
+	{"bashful", SpAttack, SpAttack},
+	{"rash", SpAttack, SpDefense},
+	{"calm", SpDefense, Attack},
+	{"gentle", SpDefense, Defense},
+	{"sassy", SpDefense, Speed},
+	{"careful", SpDefense, SpAttack},
+	{"quirky", SpDefense, SpDefense},
+}
+
+func NatureByName(name string) (Nature, bool) {
+	for _, n := range Natures {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return Nature{}, false
+}
+
+func RandomNature(r *rand.Rand) Nature {
+	return Natures[r.Intn(len(Natures))]
+}
@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type Stat int
+
+const (
+	HP Stat = iota
+	Attack
+	Defense
+	SpAttack
+	SpDefense
+	Speed
+)
+
+const (
+	MaxIV      = 31
+	MaxEV      = 252
+	MaxEVTotal = 510
+	MinLevel   = 1
+	MaxLevel   = 100
+)
+
+var statNames = [...]string{"hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+var All = []Stat{HP, Attack, Defense, SpAttack, SpDefense, Speed}
+
+func (s Stat) String() string {
+	if s < 0 || int(s) >= len(statNames) {
+		return fmt.Sprintf("stat(%d)", int(s))
+	}
+	return statNames[s]
+}
+
+func ParseStat(name string) (Stat, bool) {
+	for i, n := range statNames {
+		if n == name {
+			return Stat(i), true
+		}
+	}
+	return 0, false
+}
+
+type Spread [6]int
+
+func (s Spread) Total() int {
+	total := 0
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+func RandomIVs(r *rand.Rand) Spread {
+	var ivs Spread
+	for i := range ivs {
+		ivs[i] = r.Intn(MaxIV + 1)
+	}
+	return ivs
+}
+
+func AddEVs(evs, yield Spread) Spread {
+	for i, y := range yield {
+		room := MaxEVTotal - evs.Total()
+		if room <= 0 {
+			break
+		}
+		if y > room {
+			y = room
+		}
+		evs[i] += y
+		if evs[i] > MaxEV {
+			evs[i] = MaxEV
+		}
+	}
+	return evs
+}
+
+func Compute(base, ivs, evs Spread, level int, nature Nature) Spread {
+	if level < MinLevel {
+		level = MinLevel
+	}
+	if level > MaxLevel {
+		level = MaxLevel
+	}
+	var out Spread
+	for _, s := range All {
+		raw := (2*base[s] + ivs[s] + evs[s]/4) * level / 100
+		if s == HP {
+			// Shedinja's base HP of 1 pins it at 1 HP regardless of level.
+			if base[s] == 1 {
+				out[s] = 1
+				continue
+			}
+			out[s] = raw + level + 10
+			continue
+		}
+		out[s] = (raw + 5) * nature.Modifier(s) / 100
+	}
+	return out
+}
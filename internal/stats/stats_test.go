@@ -0,0 +1,99 @@
+package stats
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestCompute(t *testing.T) {
+	// Garchomp example from the Generation III+ stat formula reference.
+	base := Spread{108, 130, 95, 80, 85, 102}
+	ivs := Spread{24, 12, 30, 16, 23, 5}
+	evs := Spread{74, 190, 91, 48, 84, 23}
+	adamant, ok := NatureByName("adamant")
+	if !ok {
+		t.Fatalf("expected to find adamant nature")
+	}
+
+	expected := Spread{289, 278, 193, 135, 171, 171}
+	actual := Compute(base, ivs, evs, 78, adamant)
+	if actual != expected {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestComputeShedinja(t *testing.T) {
+	base := Spread{1, 90, 45, 30, 30, 40}
+	hardy, _ := NatureByName("hardy")
+	actual := Compute(base, Spread{31, 31, 31, 31, 31, 31}, Spread{}, 50, hardy)
+	if actual[HP] != 1 {
+		t.Errorf("expected 1 HP, got %d", actual[HP])
+	}
+}
+
+func TestAddEVs(t *testing.T) {
+	cases := []struct {
+		evs      Spread
+		yield    Spread
+		expected Spread
+	}{
+		{
+			evs:      Spread{},
+			yield:    Spread{0, 2, 0, 0, 0, 1},
+			expected: Spread{0, 2, 0, 0, 0, 1},
+		},
+		{
+			evs:      Spread{0, 251, 0, 0, 0, 0},
+			yield:    Spread{0, 3, 0, 0, 0, 0},
+			expected: Spread{0, 252, 0, 0, 0, 0},
+		},
+		{
+			evs:      Spread{252, 252, 5, 0, 0, 0},
+			yield:    Spread{0, 0, 3, 0, 0, 2},
+			expected: Spread{252, 252, 6, 0, 0, 0},
+		},
+	}
+
+	for _, c := range cases {
+		actual := AddEVs(c.evs, c.yield)
+		if actual != c.expected {
+			t.Errorf("AddEVs(%v, %v): expected %v, got %v", c.evs, c.yield, c.expected, actual)
+		}
+	}
+}
+
+func TestNatures(t *testing.T) {
+	if len(Natures) != 25 {
+		t.Errorf("expected 25 natures, got %d", len(Natures))
+	}
+	neutral := 0
+	for _, n := range Natures {
+		if n.Neutral() {
+			neutral++
+		}
+	}
+	if neutral != 5 {
+		t.Errorf("expected 5 neutral natures, got %d", neutral)
+	}
+
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		for _, iv := range RandomIVs(r) {
+			if iv < 0 || iv > MaxIV {
+				t.Fatalf("IV out of range: %d", iv)
+			}
+		}
+	}
+}
+
+func TestParseStat(t *testing.T) {
+	for _, s := range All {
+		parsed, ok := ParseStat(s.String())
+		if !ok || parsed != s {
+			t.Errorf("expected %v to round trip, got %v", s, parsed)
+		}
+	}
+	if _, ok := ParseStat("accuracy"); ok {
+		t.Errorf("expected accuracy to be rejected")
+	}
+}
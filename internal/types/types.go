@@ -0,0 +1,138 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+)
+
+type Fetcher func(url string, v any) error
+
+type Relations struct {
+	DoubleDamageTo []string
+	HalfDamageTo   []string
+	NoDamageTo     []string
+}
+
+type Chart struct {
+	names  []string
+	attack map[string]map[string]float64
+}
+
+type Matchup struct {
+	Type       string
+	Multiplier float64
+}
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type typeList struct {
+	Results []namedResource `json:"results"`
+}
+
+type typeDetails struct {
+	Name            string `json:"name"`
+	DamageRelations struct {
+		DoubleDamageTo   []namedResource `json:"double_damage_to"`
+		HalfDamageTo     []namedResource `json:"half_damage_to"`
+		NoDamageTo       []namedResource `json:"no_damage_to"`
+		DoubleDamageFrom []namedResource `json:"double_damage_from"`
+		HalfDamageFrom   []namedResource `json:"half_damage_from"`
+		NoDamageFrom     []namedResource `json:"no_damage_from"`
+	} `json:"damage_relations"`
+}
+
+func NewChart() *Chart {
+	return &Chart{attack: map[string]map[string]float64{}}
+}
+
+// Load fetches every type listed by the /type endpoint. Types without any
+// damage relations (unknown, shadow, stellar) are left out of the chart.
+func Load(baseURL string, fetch Fetcher) (*Chart, error) {
+	var list typeList
+	if err := fetch(baseURL+"type/?limit=100", &list); err != nil {
+		return nil, err
+	}
+	chart := NewChart()
+	for _, t := range list.Results {
+		var details typeDetails
+		if err := fetch(t.URL, &details); err != nil {
+			return nil, fmt.Errorf("loading type %s: %w", t.Name, err)
+		}
+		r := details.DamageRelations
+		if len(r.DoubleDamageTo)+len(r.HalfDamageTo)+len(r.NoDamageTo)+len(r.DoubleDamageFrom)+len(r.HalfDamageFrom)+len(r.NoDamageFrom) == 0 {
+			continue
+		}
+		chart.Add(details.Name, Relations{
+			DoubleDamageTo: names(r.DoubleDamageTo),
+			HalfDamageTo:   names(r.HalfDamageTo),
+			NoDamageTo:     names(r.NoDamageTo),
+		})
+	}
+	return chart, nil
+}
+
+func names(resources []namedResource) []string {
+	out := make([]string, 0, len(resources))
+	for _, r := range resources {
+		out = append(out, r.Name)
+	}
+	return out
+}
+
+func (c *Chart) Add(name string, rel Relations) {
+	if _, ok := c.attack[name]; !ok {
+		c.names = append(c.names, name)
+		sort.Strings(c.names)
+	}
+	row := map[string]float64{}
+	for _, t := range rel.DoubleDamageTo {
+		row[t] = 2
+	}
+	for _, t := range rel.HalfDamageTo {
+		row[t] = 0.5
+	}
+	for _, t := range rel.NoDamageTo {
+		row[t] = 0
+	}
+	c.attack[name] = row
+}
+
+func (c *Chart) Has(name string) bool {
+	_, ok := c.attack[name]
+	return ok
+}
+
+func (c *Chart) Types() []string {
+	return append([]string(nil), c.names...)
+}
+
+func (c *Chart) Effectiveness(attackType, defenderType string) float64 {
+	if m, ok := c.attack[attackType][defenderType]; ok {
+		return m
+	}
+	return 1
+}
+
+func (c *Chart) Multiplier(attackType string, defenderTypes []string) float64 {
+	multiplier := 1.0
+	for _, d := range defenderTypes {
+		multiplier *= c.Effectiveness(attackType, d)
+	}
+	return multiplier
+}
+
+// Defending lists how every attacking type fares against the given
+// defender, strongest first and alphabetically within a multiplier.
+func (c *Chart) Defending(defenderTypes []string) []Matchup {
+	matchups := make([]Matchup, 0, len(c.names))
+	for _, name := range c.names {
+		matchups = append(matchups, Matchup{Type: name, Multiplier: c.Multiplier(name, defenderTypes)})
+	}
+	sort.SliceStable(matchups, func(i, j int) bool {
+		return matchups[i].Multiplier > matchups[j].Multiplier
+	})
+	return matchups
+}
@@ -0,0 +1,90 @@
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func testChart() *Chart {
+	chart := NewChart()
+	chart.Add("electric", Relations{
+		DoubleDamageTo: []string{"water", "flying"},
+		HalfDamageTo:   []string{"electric", "grass", "dragon"},
+		NoDamageTo:     []string{"ground"},
+	})
+	chart.Add("grass", Relations{
+		DoubleDamageTo: []string{"water", "ground", "rock"},
+		HalfDamageTo:   []string{"fire", "grass", "poison", "flying", "bug", "dragon", "steel"},
+	})
+	chart.Add("ice", Relations{
+		DoubleDamageTo: []string{"grass", "ground", "flying", "dragon"},
+		HalfDamageTo:   []string{"fire", "water", "ice", "steel"},
+	})
+	chart.Add("water", Relations{
+		DoubleDamageTo: []string{"fire", "ground", "rock"},
+		HalfDamageTo:   []string{"water", "grass", "dragon"},
+	})
+	return chart
+}
+
+func TestMultiplier(t *testing.T) {
+	chart := testChart()
+	cases := []struct {
+		attack   string
+		defender []string
+		expected float64
+	}{
+		{attack: "grass", defender: []string{"water", "ground"}, expected: 4},
+		{attack: "electric", defender: []string{"water", "ground"}, expected: 0},
+		{attack: "ice", defender: []string{"dragon", "flying"}, expected: 4},
+		{attack: "grass", defender: []string{"grass", "dragon"}, expected: 0.25},
+		{attack: "water", defender: []string{"normal"}, expected: 1},
+		{attack: "unknown", defender: []string{"water"}, expected: 1},
+	}
+	for _, c := range cases {
+		if actual := chart.Multiplier(c.attack, c.defender); actual != c.expected {
+			t.Errorf("%s vs %v: expected %v, got %v", c.attack, c.defender, c.expected, actual)
+		}
+	}
+}
+
+func TestDefending(t *testing.T) {
+	chart := testChart()
+	expected := []Matchup{
+		{Type: "grass", Multiplier: 4},
+		{Type: "ice", Multiplier: 1},
+		{Type: "water", Multiplier: 1},
+		{Type: "electric", Multiplier: 0},
+	}
+	actual := chart.Defending([]string{"water", "ground"})
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, actual)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	docs := map[string]string{
+		"base/type/?limit=100": `{"results":[{"name":"ghost","url":"base/type/8/"},{"name":"unknown","url":"base/type/10001/"}]}`,
+		"base/type/8/":         `{"name":"ghost","damage_relations":{"double_damage_to":[{"name":"ghost"}],"no_damage_to":[{"name":"normal"}]}}`,
+		"base/type/10001/":     `{"name":"unknown","damage_relations":{}}`,
+	}
+	fetch := func(url string, v any) error {
+		doc, ok := docs[url]
+		if !ok {
+			return fmt.Errorf("unexpected url %s", url)
+		}
+		return json.Unmarshal([]byte(doc), v)
+	}
+
+	chart, err := Load("base/", fetch)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !chart.Has("ghost") || chart.Has("unknown") {
+		t.Errorf("expected only ghost in the chart, got %v", chart.Types())
+	}
+	if m := chart.Multiplier("ghost", []string{"normal"}); m != 0 {
+		t.Errorf("expected ghost to not affect normal, got %v", m)
+	}
+}
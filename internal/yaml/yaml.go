@@ -0,0 +1,216 @@
+// Package yaml writes Go values as YAML documents. It only encodes, and
+// follows the encoding/json conventions for field names so the same types
+// can be written in either format.
+package yaml
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Marshal returns the YAML encoding of v. Struct fields are named and
+// skipped according to their json tags and keep their declaration order;
+// map keys are sorted.
+func Marshal(v any) ([]byte, error) {
+	var b strings.Builder
+	rv := reflect.ValueOf(v)
+	if text, ok, err := inline(rv); ok || err != nil {
+		if err != nil {
+			return nil, err
+		}
+		return []byte(text + "\n"), nil
+	}
+	if err := block(&b, deref(rv), 0, false); err != nil {
+		return nil, err
+	}
+	return []byte(b.String()), nil
+}
+
+type field struct {
+	name  string
+	value reflect.Value
+}
+
+// inline formats values that fit on one line: scalars, null and empty
+// collections.
+func inline(v reflect.Value) (string, bool, error) {
+	if s, ok, err := scalar(v); ok || err != nil {
+		return s, ok, err
+	}
+	d := deref(v)
+	switch d.Kind() {
+	case reflect.Slice, reflect.Array:
+		if d.Len() == 0 {
+			return "[]", true, nil
+		}
+	case reflect.Map:
+		if d.Len() == 0 {
+			return "{}", true, nil
+		}
+	case reflect.Struct:
+		if fields, err := fieldsOf(d); err != nil || len(fields) == 0 {
+			return "{}", err == nil, err
+		}
+	}
+	return "", false, nil
+}
+
+// block writes a mapping or sequence with every line indented by indent
+// levels, except the first when the caller has already started that line.
+func block(b *strings.Builder, v reflect.Value, indent int, started bool) error {
+	pad := strings.Repeat("  ", indent)
+	switch v.Kind() {
+	case reflect.Struct, reflect.Map:
+		fields, err := fieldsOf(v)
+		if err != nil {
+			return err
+		}
+		for i, f := range fields {
+			if i > 0 || !started {
+				b.WriteString(pad)
+			}
+			b.WriteString(quote(f.name) + ":")
+			text, ok, err := inline(f.value)
+			if err != nil {
+				return err
+			}
+			if ok {
+				b.WriteString(" " + text + "\n")
+				continue
+			}
+			b.WriteString("\n")
+			child := deref(f.value)
+			// Sequences under a key sit at the key's own indentation.
+			childIndent := indent + 1
+			if child.Kind() == reflect.Slice || child.Kind() == reflect.Array {
+				childIndent = indent
+			}
+			if err := block(b, child, childIndent, false); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if i > 0 || !started {
+				b.WriteString(pad)
+			}
+			b.WriteString("- ")
+			text, ok, err := inline(v.Index(i))
+			if err != nil {
+				return err
+			}
+			if ok {
+				b.WriteString(text + "\n")
+				continue
+			}
+			if err := block(b, deref(v.Index(i)), indent+1, true); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("yaml: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func deref(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// scalar formats v when it is a single value rather than a collection.
+func scalar(v reflect.Value) (string, bool, error) {
+	if !v.IsValid() {
+		return "null", true, nil
+	}
+	if (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface || v.Kind() == reflect.Map || v.Kind() == reflect.Slice) && v.IsNil() {
+		if v.Kind() == reflect.Slice {
+			return "[]", true, nil
+		}
+		return "null", true, nil
+	}
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return "", false, err
+		}
+		return quote(string(text)), true, nil
+	}
+	v = deref(v)
+	switch v.Kind() {
+	case reflect.String:
+		return quote(v.String()), true, nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), true, nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), true, nil
+	}
+	return "", false, nil
+}
+
+func fieldsOf(v reflect.Value) ([]field, error) {
+	var fields []field
+	switch v.Kind() {
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("yaml: unsupported map key type %s", v.Type().Key())
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, k := range keys {
+			fields = append(fields, field{name: k.String(), value: v.MapIndex(k)})
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			name, opts, _ := strings.Cut(sf.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = sf.Name
+			}
+			fv := v.Field(i)
+			if strings.Contains(opts, "omitempty") && fv.IsZero() {
+				continue
+			}
+			fields = append(fields, field{name: name, value: fv})
+		}
+	default:
+		return nil, fmt.Errorf("yaml: %s is not a mapping", v.Type())
+	}
+	return fields, nil
+}
+
+// quote leaves plain strings bare and double-quotes any string a YAML
+// reader would otherwise take as another type or misparse.
+func quote(s string) string {
+	if s == "" || strings.TrimSpace(s) != s || strings.ContainsAny(s, ":#\n\"'\\{}[],&*!|>%@`") || strings.HasPrefix(s, "- ") || s == "-" || s == "~" {
+		return strconv.Quote(s)
+	}
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "null":
+		return strconv.Quote(s)
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return strconv.Quote(s)
+	}
+	return s
+}
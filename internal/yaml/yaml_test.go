@@ -0,0 +1,72 @@
+package yaml
+
+import "testing"
+
+type stat struct {
+	Name string `json:"name"`
+	Base int    `json:"base"`
+	IV   *int   `json:"iv,omitempty"`
+}
+
+type doc struct {
+	Name     string            `json:"name"`
+	Next     *string           `json:"next"`
+	Types    []string          `json:"types"`
+	Stats    []stat            `json:"stats"`
+	Empty    []string          `json:"empty"`
+	Extra    map[string]int    `json:"extra"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	internal int
+}
+
+func TestMarshal(t *testing.T) {
+	iv := 31
+	d := doc{
+		Name:  "mr-mime",
+		Types: []string{"psychic", "fairy"},
+		Stats: []stat{{Name: "hp", Base: 40, IV: &iv}, {Name: "speed", Base: 90}},
+		Extra: map[string]int{"b": 2, "a": 1},
+	}
+	expected := `name: mr-mime
+next: null
+types:
+- psychic
+- fairy
+stats:
+- name: hp
+  base: 40
+  iv: 31
+- name: speed
+  base: 90
+empty: []
+extra:
+  a: 1
+  b: 2
+`
+	actual, err := Marshal(d)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(actual) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, actual)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	cases := map[string]string{
+		"pikachu":      "pikachu",
+		"":             `""`,
+		"true":         `"true"`,
+		"25":           `"25"`,
+		"a: b":         `"a: b"`,
+		"https://x/y/": `"https://x/y/"`,
+		"two words":    "two words",
+		" padded":      `" padded"`,
+		"line\nbreak":  `"line\nbreak"`,
+	}
+	for input, expected := range cases {
+		if actual := quote(input); actual != expected {
+			t.Errorf("quote(%q): expected %s, got %s", input, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/i18n"
+)
+
+// apiLanguages are the language codes PokeAPI has names and text in.
+var apiLanguages = []string{"en", "ja", "ja-Hrkt", "roomaji", "ko", "zh-Hant", "zh-Hans", "fr", "de", "es", "it", "cs", "pt-BR"}
+
+type localizedNames []struct {
+	Name     string `json:"name"`
+	Language struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"language"`
+}
+
+// in returns the name in lang, or "" when there is no translation.
+func (names localizedNames) in(lang string) string {
+	for _, n := range names {
+		if n.Language.Name == lang {
+			return n.Name
+		}
+	}
+	return ""
+}
+
+func (config *commandConfig) language() string {
+	if config.settings.Language == "" {
+		return i18n.Fallback
+	}
+	return config.settings.Language
+}
+
+func (config *commandConfig) t(key string, args ...any) string {
+	return i18n.T(config.language(), key, args...)
+}
+
+// displayName shows a localized name followed by the name commands accept,
+// or just the latter when there is no translation.
+func displayName(localized, name string) string {
+	if localized == "" || strings.EqualFold(localized, name) {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", localized, name)
+}
+
+// speciesDisplayName looks up a species' name in the chosen language. Names
+// are only fetched when the language isn't English; any lookup failure
+// falls back to the API name.
+func (config *commandConfig) speciesDisplayName(name string) string {
+	if config.language() == i18n.Fallback || config.cache == nil {
+		return name
+	}
+	species, err := getSpecies(name, config.cache)
+	if err != nil {
+		return name
+	}
+	return displayName(species.Names.in(config.language()), name)
+}
+
+func (config *commandConfig) pokemonDisplayName(p Pokemon) string {
+	species := p.Species.Name
+	if species == "" {
+		species = p.Name
+	}
+	if config.language() == i18n.Fallback || config.cache == nil {
+		return p.Name
+	}
+	localized, err := getSpecies(species, config.cache)
+	if err != nil {
+		return p.Name
+	}
+	return displayName(localized.Names.in(config.language()), p.Name)
+}
+
+func commandLanguage(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		fmt.Println(config.t("language.current", config.language()))
+		fmt.Println(config.t("language.available", strings.Join(apiLanguages, ", ")))
+		return nil
+	}
+	code := ""
+	for _, lang := range apiLanguages {
+		if strings.EqualFold(lang, args[0]) {
+			code = lang
+		}
+	}
+	if code == "" {
+		return fmt.Errorf("unknown language: %s (use one of %s)", args[0], strings.Join(apiLanguages, ", "))
+	}
+	config.settings.Language = code
+	if code == i18n.Fallback {
+		config.settings.Language = ""
+	}
+	fmt.Println(config.t("language.set", code))
+	return saveSettings(config)
+}
@@ -1,28 +1,69 @@
 package main
 
 import (
-	"bufio"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/KindMinotaur/pokedexcli/internal/battle"
+	"github.com/KindMinotaur/pokedexcli/internal/lineedit"
 	"github.com/KindMinotaur/pokedexcli/internal/pokecache"
+	"github.com/KindMinotaur/pokedexcli/internal/render"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+	"github.com/KindMinotaur/pokedexcli/internal/types"
 )
 
 type cliCommand struct {
 	name        string
 	description string
 	callback    func(*commandConfig, []string) error
+	category    string
+	args        []argSpec
+	flags       []flagSpec
+	examples    []string
+	// saves is set for commands that change the collection, bag or money,
+	// so the save file is only rewritten after them.
+	saves bool
 }
 
 type commandConfig struct {
 	nextURL     string
 	previousURL string
 	cache       *pokecache.Cache
+	area        *LocationDetails
+	battle      *battleState
+	types       *types.Chart
+	savePath    string
+	names       map[string][]string
+	settings    userSettings
+	flags       flagValues
+	configPath  string
+	style       render.Style
+	// version and versionGroup limit version-specific data to one game.
+	version      string
+	versionGroup string
+	// startupFormat is the --output format the Pokedex was started with.
+	startupFormat string
+	// stdout is where documents go while runStructured collects a
+	// command's text.
+	stdout *os.File
+	// confirm asks a yes or no question; it is nil when nobody is there to
+	// answer, such as when commands are piped in.
+	confirm func(question string) bool
+
+	history          []historyEntry
+	historyPath      string
+	historyMax       int
+	historyFileLines int
 }
 
 type LocationAreaList struct {
@@ -56,13 +97,7 @@ type LocationDetails struct {
 		Name string `json:"name"`
 		URL  string `json:"url"`
 	} `json:"location"`
-	Names []struct {
-		Name     string `json:"name"`
-		Language struct {
-			Name string `json:"name"`
-			URL  string `json:"url"`
-		} `json:"language"`
-	} `json:"names"`
+	Names             localizedNames `json:"names"`
 	PokemonEncounters []struct {
 		Pokemon struct {
 			Name string `json:"name"`
@@ -75,11 +110,14 @@ type LocationDetails struct {
 			} `json:"version"`
 			MaxChance        int `json:"max_chance"`
 			EncounterDetails []struct {
-				MinLevel        int   `json:"min_level"`
-				MaxLevel        int   `json:"max_level"`
-				ConditionValues []any `json:"condition_values"`
-				Chance          int   `json:"chance"`
-				Method          struct {
+				MinLevel        int `json:"min_level"`
+				MaxLevel        int `json:"max_level"`
+				ConditionValues []struct {
+					Name string `json:"name"`
+					URL  string `json:"url"`
+				} `json:"condition_values"`
+				Chance int `json:"chance"`
+				Method struct {
 					Name string `json:"name"`
 					URL  string `json:"url"`
 				} `json:"method"`
@@ -365,7 +403,10 @@ type Pokemon struct {
 			URL  string `json:"url"`
 		} `json:"generation"`
 		Abilities []struct {
-			Ability  any  `json:"ability"`
+			Ability struct {
+				Name string `json:"name"`
+				URL  string `json:"url"`
+			} `json:"ability"`
 			IsHidden bool `json:"is_hidden"`
 			Slot     int  `json:"slot"`
 		} `json:"abilities"`
@@ -375,89 +416,642 @@ type Pokemon struct {
 var commands map[string]cliCommand
 var pokedex map[string]Pokemon
 
+// newGame resets the collection, storage and bag to a fresh start.
+func newGame() {
+	pokedex = make(map[string]Pokemon)
+	seen = make(map[string]bool)
+	owned = make(map[int]*ownedPokemon)
+	nextOwnedID = 1
+	party = nil
+	boxes = newBoxes()
+	bag = newBag()
+	money = startingMoney
+	unloadedSpecies = nil
+}
+
 func init() {
+	newGame()
 	commands = map[string]cliCommand{
 		"exit": {
 			name:        "exit",
 			description: "Exit the Pokedex",
+			category:    categorySystem,
 			callback:    commandExit,
 		},
 		"help": {
 			name:        "help",
 			description: "Displays a help message",
+			category:    categorySystem,
 			callback:    commandHelp,
+			args: []argSpec{
+				{name: "command", optional: true, description: "Command to show usage, flags and examples for"},
+			},
+			examples: []string{"help", "help moves"},
 		},
 		"map": {
 			name:        "map",
 			description: "Displays the next 20 pages of the Pokedex map",
+			category:    categoryNavigation,
 			callback:    commandMap,
 		},
 		"mapb": {
 			name:        "mapb",
 			description: "Displays the previous 20 pages of the Pokedex map",
+			category:    categoryNavigation,
 			callback:    commandMapb,
 		},
 		"explore": {
 			name:        "explore",
 			description: "Explore a specific location area in the Pokedex map",
+			category:    categoryNavigation,
 			callback:    commandExplore,
+			args: []argSpec{
+				{name: "area", description: "Location area name, as listed by map"},
+			},
+			flags: []flagSpec{
+				{name: "detailed", short: "d", description: "Show a table per encounter method with levels, chance and conditions"},
+				{name: "sort", short: "s", value: "key", description: "Order detailed tables by chance, rarity, name or level"},
+			},
+			examples: []string{"explore canalave-city-area", "explore great-marsh-area-1 --detailed --sort rarity"},
+		},
+		"regions": {
+			name:        "regions",
+			description: "List the regions of the Pokemon world",
+			category:    categoryNavigation,
+			callback:    commandRegions,
+			examples:    []string{"regions"},
+		},
+		"locations": {
+			name:        "locations",
+			description: "List the locations in a region",
+			category:    categoryNavigation,
+			callback:    commandLocations,
+			args: []argSpec{
+				{name: "region", description: "Region name, as listed by regions"},
+			},
+			examples: []string{"locations sinnoh"},
+		},
+		"areas": {
+			name:        "areas",
+			description: "List the explorable areas of a location",
+			category:    categoryNavigation,
+			callback:    commandAreas,
+			args: []argSpec{
+				{name: "location", description: "Location name, as listed by locations"},
+			},
+			examples: []string{"areas eterna-forest"},
+		},
+		"where": {
+			name:        "where",
+			description: "List the areas where a Pokemon can be found in the wild",
+			category:    categoryNavigation,
+			callback:    commandWhere,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon to look for"},
+			},
+			flags: []flagSpec{
+				{name: "version", value: "version", description: "Only list areas in this game version; defaults to the session version"},
+			},
+			examples: []string{"where pikachu", "where tentacool --version platinum"},
+		},
+		"goto": {
+			name:        "goto",
+			description: "Explore the area where a Pokemon is most likely to be found",
+			category:    categoryNavigation,
+			callback:    commandGoto,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon to look for"},
+			},
+			flags: []flagSpec{
+				{name: "version", value: "version", description: "Only consider areas in this game version; defaults to the session version"},
+			},
+			examples: []string{"goto pikachu", "goto tentacool --version platinum"},
 		},
 		"catch": {
 			name:        "catch",
 			description: "Catch a Pokemon by name",
+			category:    categoryCatching,
+			saves:       true,
 			callback:    commandCatch,
+			args: []argSpec{
+				{name: "pokemon", optional: true, description: "Pokemon to catch; defaults to the one you are battling"},
+			},
+			examples: []string{"catch pikachu", "catch"},
 		},
 		"inspect": {
 			name:        "inspect",
 			description: "Inspect a caught Pokemon by name",
+			category:    categoryCollection,
 			callback:    commandInspect,
+			args: []argSpec{
+				{name: "pokemon|id", description: "Species name or the id of a Pokemon you own"},
+			},
+			examples: []string{"inspect pikachu", "inspect 3"},
+		},
+		"dex": {
+			name:        "dex",
+			description: "Show completion of a regional Pokedex or a generation, or list the Pokedexes",
+			category:    categoryCollection,
+			callback:    commandDex,
+			args: []argSpec{
+				{name: "pokedex", optional: true, description: "Pokedex name, such as kanto, original-johto or national"},
+			},
+			flags: []flagSpec{
+				{name: "generation", short: "g", value: "number", kind: argInt, description: "Show completion of the species introduced in this generation instead"},
+			},
+			examples: []string{"dex", "dex kanto", "dex national", "dex --generation 4"},
 		},
 		"pokedex": {
 			name:        "pokedex",
 			description: "List all caught Pokemon",
+			category:    categoryCollection,
 			callback:    commandPokedex,
+			flags: []flagSpec{
+				{name: "sort", short: "s", value: "key", description: "Sort by id, name, type, weight, height, base_experience, total or a stat such as speed"},
+				{name: "desc", description: "Sort in descending order"},
+				{name: "type", short: "t", value: "type", kind: argName, description: "Only list Pokemon of this type"},
+				{name: "where", short: "w", value: "query", description: "Only list Pokemon matching a query such as \"base_experience>200 and has_ability:levitate\""},
+				{name: "limit", short: "n", value: "count", kind: argInt, description: "Show at most this many Pokemon per page"},
+				{name: "page", short: "p", value: "page", kind: argInt, description: "Show this page of results, counting from 1"},
+			},
+			examples: []string{"pokedex", "pokedex --sort total --desc", "pokedex --type fire --sort weight --desc", "pokedex --where \"base_experience>200 and has_ability:levitate\"", "pokedex --limit 10 --page 2"},
+		},
+		"battle": {
+			name:        "battle",
+			description: "Battle a wild Pokemon from the explored area, or one by name",
+			category:    categoryCatching,
+			saves:       true,
+			callback:    commandBattle,
+			args: []argSpec{
+				{name: "pokemon", optional: true, description: "Pokemon to battle; defaults to a random one from the explored area"},
+			},
+			examples: []string{"battle", "battle tentacool"},
+		},
+		"fight": {
+			name:        "fight",
+			description: "Use a move by name or slot number in the current battle",
+			category:    categoryCatching,
+			saves:       true,
+			callback:    commandFight,
+			args: []argSpec{
+				{name: "move|slot", description: "Move name or its slot number from the battle status"},
+			},
+			examples: []string{"fight 1", "fight thunder-shock"},
+		},
+		"run": {
+			name:        "run",
+			description: "Try to flee from the current battle",
+			category:    categoryCatching,
+			saves:       true,
+			callback:    commandRun,
+		},
+		"matchup": {
+			name:        "matchup",
+			description: "Show how an attacking type fares against a type or Pokemon",
+			category:    categoryLookup,
+			callback:    commandMatchup,
+			args: []argSpec{
+				{name: "attacking-type", description: "Type of the attacking move"},
+				{name: "type|pokemon", description: "Defending type, two types joined by /, or a Pokemon"},
+			},
+			examples: []string{"matchup electric water/flying", "matchup ice dragonite"},
+		},
+		"weak": {
+			name:        "weak",
+			description: "List the type weaknesses and resistances of a Pokemon",
+			category:    categoryLookup,
+			callback:    commandWeak,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon name or id"},
+			},
+			examples: []string{"weak quagsire"},
+		},
+		"party": {
+			name:        "party",
+			description: "Show your party, or reorder it with party swap <slot> <slot>",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandParty,
+			args: []argSpec{
+				{name: "swap", optional: true, description: "Swap two party slots"},
+				{name: "slot", kind: argInt, optional: true, description: "First slot to swap"},
+				{name: "slot", kind: argInt, optional: true, description: "Second slot to swap"},
+			},
+			examples: []string{"party", "party swap 1 3"},
+		},
+		"deposit": {
+			name:        "deposit",
+			description: "Move a party Pokemon into the PC by id",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandDeposit,
+			args: []argSpec{
+				{name: "id", kind: argInt, description: "Id of the party Pokemon"},
+			},
+			examples: []string{"deposit 2"},
+		},
+		"withdraw": {
+			name:        "withdraw",
+			description: "Move a Pokemon from the PC into your party by id",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandWithdraw,
+			args: []argSpec{
+				{name: "id", kind: argInt, description: "Id of the stored Pokemon"},
+			},
+			examples: []string{"withdraw 7"},
+		},
+		"box": {
+			name:        "box",
+			description: "List PC boxes or their contents, or rename a box",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandBox,
+			args: []argSpec{
+				{name: "list|rename", description: "Whether to list boxes or rename one"},
+				{name: "number", kind: argInt, optional: true, description: "Box number"},
+				{name: "name", kind: argText, optional: true, repeated: true, description: "New box name"},
+			},
+			examples: []string{"box list", "box list 2", "box rename 1 \"Water types\""},
+		},
+		"evolve": {
+			name:        "evolve",
+			description: "Evolve an owned Pokemon by id once it meets the conditions",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandEvolve,
+			args: []argSpec{
+				{name: "id", kind: argInt, description: "Id of the Pokemon to evolve"},
+				{name: "trade", optional: true, description: "Trade the Pokemon to trigger trade evolutions"},
+				{name: "partner", optional: true, description: "Species received in the trade, for evolutions that need a particular partner"},
+			},
+			examples: []string{"evolve 4", "evolve 4 trade", "evolve 4 trade shelmet"},
+		},
+		"evolutions": {
+			name:        "evolutions",
+			description: "Show the evolution chain of a Pokemon as a tree",
+			category:    categoryLookup,
+			callback:    commandEvolutions,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon name or id"},
+			},
+			examples: []string{"evolutions eevee"},
+		},
+		"info": {
+			name:        "info",
+			description: "Look up any Pokemon by name or id without catching it",
+			category:    categoryLookup,
+			callback:    commandInfo,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon name or id"},
+			},
+			examples: []string{"info gengar", "info 94"},
+		},
+		"sprite": {
+			name:        "sprite",
+			description: "Draw a Pokemon's sprite in the terminal",
+			category:    categoryLookup,
+			callback:    commandSprite,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon name or id"},
+			},
+			flags: []flagSpec{
+				{name: "shiny", description: "Show the shiny coloring"},
+				{name: "back", description: "Show the sprite from behind"},
+				{name: "version", value: "version", description: "Use the sprite from a game version, such as red-blue or crystal"},
+			},
+			examples: []string{"sprite pikachu", "sprite charizard --shiny --back", "sprite mew --version red-blue"},
+		},
+		"moves": {
+			name:        "moves",
+			description: "List a Pokemon's learnset",
+			category:    categoryLookup,
+			callback:    commandMoves,
+			args: []argSpec{
+				{name: "pokemon", description: "Pokemon name or id"},
+			},
+			flags: []flagSpec{
+				{name: "version-group", short: "v", value: "name", description: "Version group to list, defaults to the latest one"},
+				{name: "method", short: "m", value: "method", description: "Only moves learned by level-up, machine, egg or tutor"},
+			},
+			examples: []string{"moves pikachu", "moves pikachu --method machine", "moves pikachu -v red-blue"},
+		},
+		"move": {
+			name:        "move",
+			description: "Show power, accuracy, PP, type and effect of a move",
+			category:    categoryLookup,
+			callback:    commandMove,
+			args: []argSpec{
+				{name: "move", description: "Move name"},
+			},
+			examples: []string{"move thunderbolt"},
+		},
+		"ability": {
+			name:        "ability",
+			description: "Show an ability's effect and which Pokemon can have it",
+			category:    categoryLookup,
+			callback:    commandAbility,
+			args: []argSpec{
+				{name: "ability", description: "Ability name"},
+			},
+			examples: []string{"ability levitate", "ability stench"},
+		},
+		"item": {
+			name:        "item",
+			description: "Show an item's category, cost and effect",
+			category:    categoryLookup,
+			callback:    commandItem,
+			args: []argSpec{
+				{name: "item", description: "Item name"},
+			},
+			examples: []string{"item ultra-ball"},
+		},
+		"berry": {
+			name:        "berry",
+			description: "Show a berry's firmness, flavors and growth data",
+			category:    categoryLookup,
+			callback:    commandBerry,
+			args: []argSpec{
+				{name: "berry", description: "Berry name, without the -berry suffix"},
+			},
+			examples: []string{"berry oran"},
+		},
+		"bag": {
+			name:        "bag",
+			description: "List the items in your bag by pocket",
+			category:    categoryCollection,
+			callback:    commandBag,
+		},
+		"buy": {
+			name:        "buy",
+			description: "Buy an item, optionally with a quantity",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandBuy,
+			args: []argSpec{
+				{name: "item", description: "Item name"},
+				{name: "quantity", kind: argInt, optional: true, description: "How many to buy, 1 by default"},
+			},
+			examples: []string{"buy great-ball 5"},
+		},
+		"use": {
+			name:        "use",
+			description: "Use an item on a Pokemon by id, or throw a ball in battle",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandUse,
+			args: []argSpec{
+				{name: "item", description: "Item from your bag"},
+				{name: "id", kind: argInt, optional: true, description: "Pokemon to use it on; not needed for balls"},
+			},
+			examples: []string{"use potion 1", "use great-ball"},
+		},
+		"give": {
+			name:        "give",
+			description: "Give an item to a Pokemon by id to hold",
+			category:    categoryCollection,
+			saves:       true,
+			callback:    commandGive,
+			args: []argSpec{
+				{name: "item", description: "Item from your bag"},
+				{name: "id", kind: argInt, description: "Pokemon to hold it"},
+			},
+			examples: []string{"give kings-rock 3"},
+		},
+		"search": {
+			name:        "search",
+			description: "Find Pokemon, location areas and moves whose names contain some text",
+			category:    categoryLookup,
+			callback:    commandSearch,
+			args: []argSpec{
+				{name: "text", description: "Part of a name"},
+			},
+			examples: []string{"search chu"},
+		},
+		"history": {
+			name:        "history",
+			description: "List previous commands; re-run one with !n or !!",
+			category:    categorySystem,
+			callback:    commandHistory,
+			args: []argSpec{
+				{name: "n", kind: argInt, optional: true, description: "Only show the last n commands"},
+			},
+			examples: []string{"history 10", "!!", "!3"},
+		},
+		"alias": {
+			name:        "alias",
+			description: "List aliases, or define one",
+			category:    categorySystem,
+			callback:    commandAlias,
+			args: []argSpec{
+				{name: "name", optional: true, description: "Name of the alias"},
+				{name: "command", kind: argText, optional: true, repeated: true, description: "Command it stands for; extra arguments are appended"},
+			},
+			examples: []string{"alias", "alias ex explore"},
+		},
+		"macro": {
+			name:        "macro",
+			description: "List macros, or define one that runs several commands",
+			category:    categorySystem,
+			callback:    commandMacro,
+			args: []argSpec{
+				{name: "name", optional: true, description: "Name of the macro"},
+				{name: "commands", kind: argText, optional: true, repeated: true, description: "Commands separated by ;, with $1-$9 and $@ for arguments"},
+			},
+			examples: []string{"macro hunt \"explore $1; catch $2\""},
+		},
+		"format": {
+			name:        "format",
+			description: "Show or set the output format used when --output isn't given",
+			category:    categorySystem,
+			callback:    commandFormat,
+			args: []argSpec{
+				{name: "format", optional: true, description: "text, json or yaml"},
+			},
+			examples: []string{"format json", "map --output yaml"},
+		},
+		"language": {
+			name:        "language",
+			description: "Show or set the language for names, descriptions and messages (errors and battle logs stay in English)",
+			category:    categorySystem,
+			callback:    commandLanguage,
+			args: []argSpec{
+				{name: "code", kind: argText, optional: true, description: "Language code such as en, ja, fr, de, es, ko or zh-Hans"},
+			},
+			examples: []string{"language", "language ja"},
+		},
+		"version": {
+			name:        "version",
+			description: "Limit encounters, learnsets, held items and sprites to one game version",
+			category:    categorySystem,
+			callback:    commandVersion,
+			args: []argSpec{
+				{name: "name|all", optional: true, description: "Game version such as platinum, or all to clear it"},
+			},
+			examples: []string{"version platinum", "version all"},
+		},
+		"unalias": {
+			name:        "unalias",
+			description: "Remove an alias or macro",
+			category:    categorySystem,
+			callback:    commandUnalias,
+			args: []argSpec{
+				{name: "name", description: "Alias or macro to remove"},
+			},
+			examples: []string{"unalias ex"},
 		},
 	}
 }
 
 func main() {
-	scanner := bufio.NewScanner(os.Stdin)
+	output := flag.String("output", "", "output format: text, json or yaml")
+	flag.Parse()
+	if *output != "" {
+		if err := checkFormat(*output); err != nil {
+			fmt.Println(err)
+			os.Exit(2)
+		}
+	}
+
 	config := &commandConfig{
-		cache: pokecache.NewCache(5 * time.Minute),
+		cache:       pokecache.NewCache(5 * time.Minute),
+		savePath:    defaultSavePath(),
+		historyPath: defaultHistoryPath(),
+		historyMax:  historySize(),
+		configPath:  defaultConfigPath(),
+	}
+	config.startupFormat = *output
+	settings, err := loadSettings(config.configPath)
+	if err != nil {
+		config.reportError(fmt.Errorf("could not load config file: %w", err))
+	}
+	config.settings = settings
+	config.style = render.Detect(os.Stdout)
+	if err := loadGame(config.savePath, config.cache); err != nil {
+		config.reportError(fmt.Errorf("could not load save file: %w", err))
+	}
+	history, historyLines, err := loadHistory(config.historyPath, config.historyMax)
+	if err != nil {
+		config.reportError(fmt.Errorf("could not load history: %w", err))
+	}
+	config.history = history
+	config.historyFileLines = historyLines
+
+	editor := lineedit.New(os.Stdin, os.Stdout)
+	editor.Prompt = "Pokedex > "
+	editor.Complete = completer(config)
+	for _, e := range config.history {
+		editor.AddHistory(e.Line)
+	}
+	if editor.Interactive() {
+		config.confirm = func(question string) bool {
+			prompt := editor.Prompt
+			editor.Prompt = question
+			defer func() { editor.Prompt = prompt }()
+			answer, err := editor.ReadLine()
+			answer = strings.ToLower(strings.TrimSpace(answer))
+			return err == nil && (answer == "y" || answer == "yes")
+		}
 	}
 
 	for {
-		fmt.Print("Pokedex > ")
-		scanner.Scan()
-		text := scanner.Text()
-		cleanText := cleanInput(text)
+		text, err := editor.ReadLine()
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if err != nil {
+			commandExit(config, nil)
+		}
+		text, expanded, err := expandHistory(config.history, text)
+		if err != nil {
+			config.reportError(err)
+			continue
+		}
+		if expanded && !config.structuredOutput() {
+			fmt.Println(text)
+		}
+		if strings.TrimSpace(text) != "" {
+			editor.AddHistory(text)
+			if err := recordHistory(config, text); err != nil {
+				config.reportError(fmt.Errorf("could not write history: %w", err))
+			}
+		}
+		dispatch(config, text)
+	}
+}
+
+func dispatch(config *commandConfig, text string) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		config.reportError(err)
+		return
+	}
+	lines, err := expandCommand(config, tokens, map[string]bool{})
+	if err != nil {
+		config.reportError(err)
+		return
+	}
 
-		if len(cleanText) == 0 {
+	for _, words := range lines {
+		if len(words) == 0 {
 			continue
 		}
 
-		firstWord := cleanText[0]
+		firstWord := words[0]
 
-		if cmd, exists := commands[firstWord]; exists {
-			cmd.callback(config, cleanText[1:])
+		cmd, exists := commands[firstWord]
+		if !exists {
+			config.reportError(errors.New(config.t("unknown_command", firstWord)))
+			continue
+		}
+		if slices.Contains(words[1:], "--help") {
+			if config.structuredOutput() {
+				config.emit(struct {
+					Usage string `json:"usage"`
+				}{cmd.usage()})
+				continue
+			}
+			fmt.Println(config.t("help.usage", cmd.usage()))
+			continue
+		}
+		args, flags, err := cmd.parseArgs(words[1:])
+		if err != nil {
+			config.reportError(err)
+			continue
+		}
+		config.flags = flags
+		if err := checkFormat(config.outputFormat()); err != nil {
+			fmt.Println(err)
+			config.flags = nil
+			continue
+		}
+		run := func() error { return cmd.callback(config, args) }
+		if config.structuredOutput() {
+			err = config.runStructured(cmd.name, run)
 		} else {
-			fmt.Printf("Unknown command: %s\n", firstWord)
+			err = run()
+		}
+		if err != nil {
+			config.reportError(err)
+		}
+		config.flags = nil
+		if !cmd.saves {
+			continue
+		}
+		if err := saveGame(config.savePath); err != nil {
+			config.reportError(fmt.Errorf("could not save: %w", err))
 		}
 	}
 }
 
 func commandExit(config *commandConfig, args []string) error {
-	fmt.Println("Closing the Pokedex... Goodbye!")
-	os.Exit(0)
-	return nil
-}
-
-func commandHelp(config *commandConfig, args []string) error {
-	fmt.Println("Welcome to the Pokedex!")
-	fmt.Println("Usage:")
-	for name, cmd := range commands {
-		fmt.Printf("%s: %s\n", name, cmd.description)
+	if err := saveGame(config.savePath); err != nil {
+		config.reportError(fmt.Errorf("could not save: %w", err))
 	}
+	if !config.structuredOutput() {
+		fmt.Println(config.t("goodbye"))
+	}
+	os.Exit(0)
 	return nil
 }
 
@@ -473,10 +1067,7 @@ func commandMap(config *commandConfig, args []string) error {
 		}
 		config.nextURL = list.Next
 		config.previousURL = list.Previous
-		for _, result := range list.Results {
-			fmt.Println(result.Name)
-		}
-		return nil
+		return printLocationPage(config, list)
 	}
 
 	res, err := http.Get(url)
@@ -501,19 +1092,25 @@ func commandMap(config *commandConfig, args []string) error {
 	config.nextURL = list.Next
 	config.previousURL = list.Previous
 
+	config.cache.Add(url, body)
+
+	return printLocationPage(config, list)
+}
+
+func printLocationPage(config *commandConfig, list LocationAreaList) error {
+	if config.structuredOutput() {
+		return config.emit(newLocationPageDoc(list))
+	}
 	for _, result := range list.Results {
 		fmt.Println(result.Name)
 	}
-
-	config.cache.Add(url, body)
-
 	return nil
 }
 
 func commandMapb(config *commandConfig, args []string) error {
 	url := "https://pokeapi.co/api/v2/location-area/"
 	if config.previousURL == "" {
-		fmt.Println("you're on the first page")
+		fmt.Println(config.t("map.first_page"))
 		return nil
 	}
 	if data, ok := config.cache.Get(url); ok {
@@ -523,10 +1120,7 @@ func commandMapb(config *commandConfig, args []string) error {
 		}
 		config.nextURL = list.Next
 		config.previousURL = list.Previous
-		for _, result := range list.Results {
-			fmt.Println(result.Name)
-		}
-		return nil
+		return printLocationPage(config, list)
 	}
 	res, err := http.Get(config.previousURL)
 	if err != nil {
@@ -550,13 +1144,9 @@ func commandMapb(config *commandConfig, args []string) error {
 	config.nextURL = list.Next
 	config.previousURL = list.Previous
 
-	for _, result := range list.Results {
-		fmt.Println(result.Name)
-	}
-
 	config.cache.Add(url, body)
 
-	return nil
+	return printLocationPage(config, list)
 }
 
 func commandExplore(config *commandConfig, args []string) error {
@@ -565,84 +1155,126 @@ func commandExplore(config *commandConfig, args []string) error {
 	}
 	areaName := args[0]
 	url := fmt.Sprintf("https://pokeapi.co/api/v2/location-area/%s", areaName)
-	if data, ok := config.cache.Get(url); ok {
-		var location LocationDetails
-		if err := json.Unmarshal(data, &location); err != nil {
-			return err
-		}
-		for _, result := range location.PokemonEncounters {
-			fmt.Println(result.Pokemon.Name)
+	var location LocationDetails
+	if err := fetchResource(url, config.cache, &location); err != nil {
+		return config.withSuggestions(locationAreaNames, areaName, err)
+	}
+	config.area = &location
+	detailed := config.flags.has("detailed")
+	var methods []encounterMethod
+	if detailed {
+		methods = config.encounterMethods(&location)
+		for _, m := range methods {
+			if err := sortEncounters(m.Pokemon, config.flags["sort"]); err != nil {
+				return err
+			}
 		}
-		return nil
 	}
-	res, err := http.Get(url)
-	if err != nil {
-		return err
+	if config.structuredOutput() {
+		doc := newExploreDoc(location, config.version, config.areaEncounters(&location))
+		doc.Methods = methods
+		return config.emit(doc)
 	}
-	body, err := io.ReadAll(res.Body)
-	defer res.Body.Close()
-	var location LocationDetails
-	err = json.Unmarshal(body, &location)
-	if err != nil {
-		return err
+	fmt.Println(config.t("explore.title", displayName(location.Names.in(config.language()), location.Name)))
+	if detailed {
+		return printEncounterMethods(config, methods)
 	}
-	for _, result := range location.PokemonEncounters {
-		fmt.Println(result.Pokemon.Name)
+	fmt.Println(config.t("explore.found"))
+	for _, e := range config.areaEncounters(&location) {
+		if config.version == "" {
+			fmt.Printf(" - %s\n", config.speciesDisplayName(e.Name))
+			continue
+		}
+		fmt.Printf(" - %s (Lv%d-%d, %d%%)\n", config.speciesDisplayName(e.Name), e.MinLevel, e.MaxLevel, e.Chance)
 	}
 	return nil
 }
 
 func getPokemon(pokemonName string, cache *pokecache.Cache) (Pokemon, error) {
-	url := fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", pokemonName)
-	if data, ok := cache.Get(url); ok {
-		var pokemon Pokemon
-		if err := json.Unmarshal(data, &pokemon); err != nil {
-			return Pokemon{}, err
-		}
-		return pokemon, nil
-	}
-	res, err := http.Get(url)
-	if err != nil {
-		return Pokemon{}, err
-	}
-	body, err := io.ReadAll(res.Body)
-	defer res.Body.Close()
-	if err != nil {
-		return Pokemon{}, err
-	}
-	cache.Add(url, body)
 	var pokemon Pokemon
-	err = json.Unmarshal(body, &pokemon)
-	if err != nil {
-		return Pokemon{}, err
-	}
-	return pokemon, nil
+	err := fetchResource(fmt.Sprintf("https://pokeapi.co/api/v2/pokemon/%s", pokemonName), cache, &pokemon)
+	return pokemon, err
 }
 
 func commandCatch(config *commandConfig, args []string) error {
+	if config.battle != nil && (len(args) == 0 || args[0] == config.battle.wild.Species) {
+		if err := takeBall("poke-ball"); err != nil {
+			return err
+		}
+		return catchInBattle(config, "poke-ball", 1)
+	}
 	if len(args) == 0 {
 		return fmt.Errorf("catch command requires a pokemon name")
 	}
 	pokemonName := args[0]
-	fmt.Printf("Throwing a Pokeball at %s...\n", pokemonName)
 	pokemon, err := getPokemon(pokemonName, config.cache)
 	if err != nil {
+		return config.withSuggestions(pokemonNames, pokemonName, err)
+	}
+	markSeen(pokemon)
+	if err := takeBall("poke-ball"); err != nil {
 		return err
 	}
-	r := rand.New(rand.NewSource(time.Now().UnixNano())).Intn(100)
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	var caught *ownedPokemon
+	if rollCatch(rng, pokemon.BaseExperience, 1) {
+		pokedex[pokemonName] = pokemon
+		caught = newOwnedPokemon(pokemonName, 5+rng.Intn(26), rng)
+		caught.Gender = rollGender(config, pokemonName, rng)
+	}
+	if config.structuredOutput() {
+		doc := catchDoc{Pokemon: pokemonName, Caught: caught != nil}
+		if caught != nil {
+			doc.Owned = &ownedDoc{ID: caught.ID, Level: caught.Level, Nature: caught.Nature, Gender: caught.Gender}
+		}
+		return config.emit(doc)
+	}
+	fmt.Println(config.t("catch.throw", pokemonName))
+	if caught == nil {
+		fmt.Println(config.t("catch.escaped", pokemonName))
+		return nil
+	}
+	fmt.Println(config.t("catch.caught", pokemonName))
+	fmt.Println(config.t("catch.registered", pokemonName, caught.ID, caught.Level, caught.Nature))
+	return nil
+}
+
+// rollCatch scales the catch odds down with base experience, so stronger
+// species escape more often, and then applies multiplier on top.
+func rollCatch(rng *rand.Rand, baseExperience int, multiplier float64) bool {
+	r := rng.Intn(100)
 	minChance := 10
 	maxChance := 90
 	minBase := 36
 	maxBase := 635
-	base := pokemon.BaseExperience
-	t := float64(base-minBase) / float64(maxBase-minBase)
+	t := float64(baseExperience-minBase) / float64(maxBase-minBase)
+	t = min(max(t, 0), 1)
 	chance := maxChance - int(t*float64(maxChance-minChance))
-	if r < int(chance) {
-		fmt.Printf("%s escaped!\n", pokemonName)
-	} else {
-		fmt.Printf("%s was caught!\n", pokemonName)
-		pokedex[pokemonName] = pokemon
+	return float64(r) < float64(chance)*multiplier
+}
+
+func catchInBattle(config *commandConfig, ball string, ballMultiplier float64) error {
+	state := config.battle
+	wild := state.engine.Wild
+	fmt.Println(config.t("catch.throw_ball", ball, wild.Name))
+	multiplier := ballMultiplier * battle.CatchRateMultiplier(wild.HP, wild.Stats[stats.HP]) * battle.StatusCatchBonus(wild.Status)
+	if rollCatch(state.engine.Rand, state.wildSpecies.BaseExperience, multiplier) {
+		fmt.Println(config.t("catch.caught", wild.Name))
+		pokedex[wild.Name] = state.wildSpecies
+		syncInstance(state.wild, wild)
+		caught := registerOwned(state.wild)
+		fmt.Println(config.t("catch.registered", wild.Name, caught.ID, caught.Level, caught.Nature))
+		syncInstance(state.lead, state.engine.Player)
+		config.battle = nil
+		return nil
 	}
+	fmt.Println(config.t("catch.escaped", wild.Name))
+	log, err := state.engine.Skip()
+	if err != nil {
+		return err
+	}
+	printBattleLog(log)
+	finishBattle(config)
 	return nil
 }
 
@@ -650,34 +1282,103 @@ func commandInspect(config *commandConfig, args []string) error {
 	if len(args) == 0 {
 		return fmt.Errorf("inspect command requires a pokemon name")
 	}
+	instance, hasInstance := findOwned(args[0])
 	pokemonName := args[0]
-	if p, exists := pokedex[pokemonName]; exists {
-		fmt.Printf("Name: %s\n", p.Name)
-		fmt.Printf("Height: %d\n", p.Height)
-		fmt.Printf("Weight: %d\n", p.Weight)
-		fmt.Printf("Stats:\n")
-		for _, stat := range p.Stats {
-			fmt.Printf("  -%s: %d\n", stat.Stat.Name, stat.BaseStat)
+	if hasInstance {
+		pokemonName = instance.Species
+	}
+	if config.structuredOutput() {
+		p, exists := pokedex[pokemonName]
+		if !exists {
+			return fmt.Errorf("%s", config.t("inspect.not_caught"))
 		}
-		fmt.Printf("Types:\n")
-		for _, typ := range p.Types {
-			fmt.Printf("  -%s\n", typ.Type.Name)
+		return config.emit(newInspectDoc(p, instance))
+	}
+	if p, exists := pokedex[pokemonName]; exists {
+		fmt.Println(config.t("inspect.name", config.pokemonDisplayName(p)))
+		fmt.Println(config.t("inspect.height", p.Height))
+		fmt.Println(config.t("inspect.weight", p.Weight))
+		if hasInstance {
+			fmt.Println(config.t("inspect.id", instance.ID))
+			fmt.Println(config.t("inspect.level", instance.Level))
+			fmt.Println(config.t("inspect.nature", instance.Nature))
+			if instance.Gender != "" {
+				fmt.Println(config.t("inspect.gender", instance.Gender))
+			}
 		}
+		fmt.Println(config.t("types", render.Badges(typeNames(p), config.style)))
+		fmt.Println(config.t("stats"))
+		return printStatTable(config, p, instance)
 	} else {
-		fmt.Printf("you have not caught that pokemon\n")
+		fmt.Println(config.t("inspect.not_caught"))
 	}
 	return nil
 }
 
+// defaultPageSize is the page size when --page is given without --limit.
+const defaultPageSize = 20
+
 func commandPokedex(config *commandConfig, args []string) error {
+	var filters []pokemonFilter
+	if t := config.flags["type"]; t != "" {
+		filters = append(filters, func(p Pokemon) bool { return hasType(p, t) })
+	}
+	if q := config.flags["where"]; q != "" {
+		filter, err := parseQuery(q)
+		if err != nil {
+			return err
+		}
+		filters = append(filters, filter)
+	}
+	list := filterPokedex(sortedPokedex(), filters...)
+	if err := sortPokedex(list, config.flags["sort"], config.flags.has("desc")); err != nil {
+		return err
+	}
+	matches := len(list)
+	limit, page := max(matches, 1), 1
+	if config.flags.has("page") {
+		limit = defaultPageSize
+		page, _ = strconv.Atoi(config.flags["page"])
+	}
+	if config.flags.has("limit") {
+		limit, _ = strconv.Atoi(config.flags["limit"])
+	}
+	if limit < 1 || page < 1 {
+		return errors.New(config.t("pokedex.bad_page"))
+	}
+	start, end := pageBounds(matches, limit, page)
+	list = list[start:end]
+
+	if config.structuredOutput() {
+		doc := pokedexDoc{Count: len(pokedex), Matches: matches, Pokemon: []pokedexEntryDoc{}}
+		for _, p := range list {
+			doc.Pokemon = append(doc.Pokemon, pokedexEntryDoc{ID: p.ID, Name: p.Name, Types: typeNames(p)})
+		}
+		return config.emit(doc)
+	}
 	if len(pokedex) == 0 {
-		fmt.Println("You have not caught any Pokemon yet.")
+		fmt.Println(config.t("pokedex.empty"))
 		return nil
-	} else {
-		fmt.Println("Caught Pokemon:")
-		for name := range pokedex {
-			fmt.Printf(" - %s\n", name)
+	}
+	if matches == 0 {
+		fmt.Println(config.t("pokedex.no_match"))
+		return nil
+	}
+	if len(list) == 0 {
+		return errors.New(config.t("pokedex.past_end", page, (matches+limit-1)/limit))
+	}
+	fmt.Println(config.t("pokedex.title"))
+	if err := printPokedexTable(config, list); err != nil {
+		return err
+	}
+	if start > 0 || end < matches {
+		fmt.Print(config.t("pokedex.showing", start+1, end, matches))
+		if end < matches && config.flags.has("limit") {
+			fmt.Print(config.t("pokedex.more_limit", limit, page+1))
+		} else if end < matches {
+			fmt.Print(config.t("pokedex.more", page+1))
 		}
+		fmt.Println()
 	}
 	return nil
 }
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/types"
+)
+
+func (config *commandConfig) typeChart() (*types.Chart, error) {
+	if config.types != nil {
+		return config.types, nil
+	}
+	chart, err := types.Load(apiBaseURL, func(url string, v any) error {
+		return fetchResource(url, config.cache, v)
+	})
+	if err != nil {
+		return nil, err
+	}
+	config.types = chart
+	return chart, nil
+}
+
+// defenderTypes accepts a type name, a slash-separated dual type such as
+// water/ground, or a Pokemon name.
+func defenderTypes(config *commandConfig, chart *types.Chart, arg string) ([]string, error) {
+	parts := strings.Split(arg, "/")
+	allTypes := true
+	for _, part := range parts {
+		if !chart.Has(part) {
+			allTypes = false
+		}
+	}
+	if allTypes {
+		return parts, nil
+	}
+	pokemon, err := getPokemon(arg, config.cache)
+	if err != nil {
+		return nil, config.withSuggestions(pokemonNames, arg, err)
+	}
+	return typeNames(pokemon), nil
+}
+
+func formatMultiplier(m float64) string {
+	return strconv.FormatFloat(m, 'f', -1, 64) + "x"
+}
+
+func commandMatchup(config *commandConfig, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("matchup command requires an attacking type and a defending type or pokemon")
+	}
+	chart, err := config.typeChart()
+	if err != nil {
+		return err
+	}
+	attack := args[0]
+	if !chart.Has(attack) {
+		return fmt.Errorf("unknown type: %s", attack)
+	}
+	defenders, err := defenderTypes(config, chart, args[1])
+	if err != nil {
+		return err
+	}
+	if config.structuredOutput() {
+		doc := matchupDoc{Attack: attack, Defenders: []effectivenessDoc{}, Multiplier: chart.Multiplier(attack, defenders)}
+		for _, d := range defenders {
+			doc.Defenders = append(doc.Defenders, effectivenessDoc{Type: d, Multiplier: chart.Effectiveness(attack, d)})
+		}
+		return config.emit(doc)
+	}
+	for _, d := range defenders {
+		fmt.Printf("%s vs %s: %s\n", attack, d, formatMultiplier(chart.Effectiveness(attack, d)))
+	}
+	fmt.Printf("%s vs %s: %s\n", attack, strings.Join(defenders, "/"), formatMultiplier(chart.Multiplier(attack, defenders)))
+	return nil
+}
+
+func commandWeak(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("weak command requires a pokemon name")
+	}
+	chart, err := config.typeChart()
+	if err != nil {
+		return err
+	}
+	defenders, err := defenderTypes(config, chart, args[0])
+	if err != nil {
+		return err
+	}
+	if config.structuredOutput() {
+		doc := weaknessDoc{Name: args[0], Types: defenders, Takes: []effectivenessDoc{}}
+		for _, m := range chart.Defending(defenders) {
+			if m.Multiplier != 1 {
+				doc.Takes = append(doc.Takes, effectivenessDoc{Type: m.Type, Multiplier: m.Multiplier})
+			}
+		}
+		return config.emit(doc)
+	}
+	fmt.Println(config.t("matchup.takes", args[0], strings.Join(defenders, "/")))
+	groups := map[float64][]string{}
+	for _, m := range chart.Defending(defenders) {
+		groups[m.Multiplier] = append(groups[m.Multiplier], m.Type)
+	}
+	for _, m := range []float64{4, 2, 0.5, 0.25, 0} {
+		if len(groups[m]) > 0 {
+			fmt.Printf("  %s: %s\n", formatMultiplier(m), strings.Join(groups[m], ", "))
+		}
+	}
+	return nil
+}
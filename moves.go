@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var learnMethodAliases = map[string]string{
+	"level-up": "level-up",
+	"level":    "level-up",
+	"machine":  "machine",
+	"tm":       "machine",
+	"egg":      "egg",
+	"tutor":    "tutor",
+}
+
+type learnsetEntry struct {
+	Move   string `json:"move"`
+	Method string `json:"method"`
+	Level  int    `json:"level"`
+}
+
+func resourceID(url string) int {
+	parts := strings.Split(strings.TrimSuffix(url, "/"), "/")
+	id, _ := strconv.Atoi(parts[len(parts)-1])
+	return id
+}
+
+// latestVersionGroup picks the newest version group a Pokemon has move data
+// for, judging by the id in the resource URL.
+func latestVersionGroup(p Pokemon) string {
+	latest, latestID := "", -1
+	for _, m := range p.Moves {
+		for _, d := range m.VersionGroupDetails {
+			if id := resourceID(d.VersionGroup.URL); id > latestID {
+				latest, latestID = d.VersionGroup.Name, id
+			}
+		}
+	}
+	return latest
+}
+
+func learnset(p Pokemon, versionGroup, method string) []learnsetEntry {
+	seen := map[learnsetEntry]bool{}
+	var entries []learnsetEntry
+	for _, m := range p.Moves {
+		for _, d := range m.VersionGroupDetails {
+			if d.VersionGroup.Name != versionGroup {
+				continue
+			}
+			if method != "" && d.MoveLearnMethod.Name != method {
+				continue
+			}
+			e := learnsetEntry{Move: m.Move.Name, Method: d.MoveLearnMethod.Name, Level: d.LevelLearnedAt}
+			if !seen[e] {
+				seen[e] = true
+				entries = append(entries, e)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.Level != b.Level {
+			return a.Level < b.Level
+		}
+		return a.Move < b.Move
+	})
+	return entries
+}
+
+func commandMoves(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("moves command requires a pokemon name")
+	}
+	pokemonName := args[0]
+	versionGroup := config.flags["version-group"]
+	method := ""
+	if value, ok := config.flags["method"]; ok {
+		canonical, known := learnMethodAliases[value]
+		if !known {
+			return fmt.Errorf("unknown learn method: %s (use level-up, machine, egg or tutor)", value)
+		}
+		method = canonical
+	}
+
+	p, err := getPokemon(pokemonName, config.cache)
+	if err != nil {
+		return config.withSuggestions(pokemonNames, pokemonName, err)
+	}
+	if versionGroup == "" {
+		versionGroup = config.versionGroup
+	}
+	if versionGroup == "" {
+		versionGroup = latestVersionGroup(p)
+	}
+	entries := learnset(p, versionGroup, method)
+	if config.structuredOutput() {
+		return config.emit(learnsetDoc{Pokemon: p.Name, VersionGroup: versionGroup, Moves: append([]learnsetEntry{}, entries...)})
+	}
+	if len(entries) == 0 {
+		fmt.Println(config.t("moves.none", p.Name, versionGroup))
+		return nil
+	}
+	fmt.Println(config.t("moves.title", p.Name, versionGroup))
+	currentMethod := ""
+	for _, e := range entries {
+		if e.Method != currentMethod {
+			currentMethod = e.Method
+			fmt.Printf("%s:\n", currentMethod)
+		}
+		if e.Method == "level-up" {
+			fmt.Printf("  Lv%-3d %s\n", e.Level, e.Move)
+		} else {
+			fmt.Printf("  %s\n", e.Move)
+		}
+	}
+	return nil
+}
+
+func moveEffect(move MoveDetails) string {
+	for _, e := range move.EffectEntries {
+		if e.Language.Name == "en" {
+			effect := strings.ReplaceAll(e.ShortEffect, "$effect_chance", strconv.Itoa(move.EffectChance))
+			return strings.Join(strings.Fields(effect), " ")
+		}
+	}
+	return ""
+}
+
+func commandMove(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("move command requires a move name")
+	}
+	move, err := getMove(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(moveNames, args[0], err)
+	}
+	orDash := func(v int) string {
+		if v == 0 {
+			return "-"
+		}
+		return strconv.Itoa(v)
+	}
+	fmt.Println(config.t("name", move.Name))
+	fmt.Println(config.t("move.type", move.Type.Name))
+	fmt.Println(config.t("move.class", move.DamageClass.Name))
+	fmt.Println(config.t("move.power", orDash(move.Power)))
+	fmt.Println(config.t("move.accuracy", orDash(move.Accuracy)))
+	fmt.Printf("PP: %d\n", move.PP)
+	if move.Priority != 0 {
+		fmt.Println(config.t("move.priority", move.Priority))
+	}
+	if effect := moveEffect(move); effect != "" {
+		fmt.Println(config.t("effect", effect))
+	}
+	return nil
+}
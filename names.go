@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/fuzzy"
+)
+
+const (
+	pokemonNames      = "pokemon"
+	locationAreaNames = "location-area"
+	moveNames         = "move"
+	versionNames      = "version"
+	regionNames       = "region"
+	locationNames     = "location"
+	pokedexNames      = "pokedex"
+	abilityNames      = "ability"
+	generationNames   = "generation"
+	maxSuggestions    = 3
+)
+
+var indexedResources = []string{pokemonNames, locationAreaNames, moveNames}
+
+type namedResourceList struct {
+	Next    string `json:"next"`
+	Results []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"results"`
+}
+
+// resourceNames lists every name of a resource, walking the paginated list
+// endpoint through the cache the first time it is asked for.
+func (config *commandConfig) resourceNames(resource string) ([]string, error) {
+	if names, ok := config.names[resource]; ok {
+		return names, nil
+	}
+	var names []string
+	url := apiBaseURL + resource + "?limit=1000"
+	for url != "" {
+		var page namedResourceList
+		if err := fetchResource(url, config.cache, &page); err != nil {
+			return nil, err
+		}
+		for _, r := range page.Results {
+			names = append(names, r.Name)
+		}
+		url = page.Next
+	}
+	if config.names == nil {
+		config.names = map[string][]string{}
+	}
+	config.names[resource] = names
+	return names, nil
+}
+
+// withSuggestions turns a not-found lookup into an error that offers the
+// closest known names. Other errors are returned unchanged.
+func (config *commandConfig) withSuggestions(resource, name string, err error) error {
+	var notFound *notFoundError
+	if !errors.As(err, &notFound) {
+		return err
+	}
+	names, indexErr := config.resourceNames(resource)
+	if indexErr != nil {
+		return fmt.Errorf("unknown %s: %s", resource, name)
+	}
+	suggestions := fuzzy.Suggest(name, names, maxSuggestions)
+	if len(suggestions) == 0 {
+		return fmt.Errorf("unknown %s: %s", resource, name)
+	}
+	return fmt.Errorf("unknown %s: %s. Did you mean %s?", resource, name, strings.Join(suggestions, ", "))
+}
+
+func commandSearch(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("search command requires some text to look for")
+	}
+	found := false
+	results := map[string][]string{}
+	for _, resource := range indexedResources {
+		names, err := config.resourceNames(resource)
+		if err != nil {
+			return err
+		}
+		matches := fuzzy.Search(args[0], names)
+		if len(matches) == 0 {
+			continue
+		}
+		found = true
+		results[resource] = matches
+		if config.structuredOutput() {
+			continue
+		}
+		fmt.Printf("%s:\n", resource)
+		for _, m := range matches {
+			fmt.Printf("  %s\n", m)
+		}
+	}
+	if config.structuredOutput() {
+		return config.emit(results)
+	}
+	if !found {
+		fmt.Println(config.t("search.none", args[0]))
+	}
+	return nil
+}
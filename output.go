@@ -0,0 +1,361 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+	"github.com/KindMinotaur/pokedexcli/internal/yaml"
+)
+
+const (
+	formatText = "text"
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// outputFlag is accepted by every command on top of its own flags.
+var outputFlag = flagSpec{name: "output", short: "o", value: "format", description: "Print text, json or yaml"}
+
+func checkFormat(format string) error {
+	switch format {
+	case formatText, formatJSON, formatYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format: %s (use text, json or yaml)", format)
+}
+
+// outputFormat picks the format for the running command: its --output flag,
+// then the format given when the Pokedex was started, then the saved
+// setting.
+func (config *commandConfig) outputFormat() string {
+	for _, format := range []string{config.flags["output"], config.startupFormat, config.settings.Format} {
+		if format != "" {
+			return format
+		}
+	}
+	return formatText
+}
+
+func (config *commandConfig) structuredOutput() bool {
+	return config.outputFormat() != formatText
+}
+
+// emit writes doc in the structured output format.
+func (config *commandConfig) emit(doc any) error {
+	var data []byte
+	var err error
+	if config.outputFormat() == formatYAML {
+		// Each document starts with a marker so a session's output reads
+		// as a YAML stream.
+		data, err = yaml.Marshal(doc)
+		data = append([]byte("---\n"), data...)
+	} else {
+		data, err = json.MarshalIndent(doc, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	out := os.Stdout
+	if config.stdout != nil {
+		out = config.stdout
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+var escapeSequence = regexp.MustCompile(`\x1b\[[0-9;]*[A-Za-z]`)
+
+type messagesDoc struct {
+	Command  string   `json:"command"`
+	Messages []string `json:"messages"`
+}
+
+// runStructured runs a command under structured output. Documents from emit
+// go straight to stdout, while any text the command prints is collected and
+// emitted afterwards as a messages document, so the stream stays parseable
+// for commands without a document of their own.
+func (config *commandConfig) runStructured(name string, run func() error) error {
+	r, w, err := os.Pipe()
+	if err != nil {
+		return run()
+	}
+	config.stdout, os.Stdout = os.Stdout, w
+	captured := make(chan []byte)
+	go func() {
+		data, _ := io.ReadAll(r)
+		captured <- data
+	}()
+	runErr := run()
+	w.Close()
+	os.Stdout, config.stdout = config.stdout, nil
+	text := escapeSequence.ReplaceAllString(string(<-captured), "")
+	r.Close()
+
+	var messages []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			messages = append(messages, line)
+		}
+	}
+	if len(messages) > 0 {
+		if err := config.emit(messagesDoc{Command: name, Messages: messages}); err != nil {
+			return err
+		}
+	}
+	return runErr
+}
+
+// reportError prints a command error, as an error document when the output
+// is structured so scripts always get something they can parse.
+func (config *commandConfig) reportError(err error) {
+	if !config.structuredOutput() {
+		fmt.Println(err)
+		return
+	}
+	if emitErr := config.emit(struct {
+		Error string `json:"error"`
+	}{err.Error()}); emitErr != nil {
+		fmt.Println(err)
+	}
+}
+
+func commandFormat(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		format := config.settings.Format
+		if format == "" {
+			format = formatText
+		}
+		fmt.Println(config.t("format.current", format))
+		return nil
+	}
+	if err := checkFormat(args[0]); err != nil {
+		return err
+	}
+	config.settings.Format = args[0]
+	if args[0] == formatText {
+		config.settings.Format = ""
+	}
+	fmt.Println(config.t("format.set", args[0]))
+	return saveSettings(config)
+}
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type locationPageDoc struct {
+	Count    int             `json:"count"`
+	Next     *string         `json:"next"`
+	Previous *string         `json:"previous"`
+	Results  []namedResource `json:"results"`
+}
+
+type encounterDoc struct {
+	Name     string `json:"name"`
+	URL      string `json:"url"`
+	Chance   int    `json:"chance"`
+	MinLevel int    `json:"min_level"`
+	MaxLevel int    `json:"max_level"`
+}
+
+type exploreDoc struct {
+	Name     string            `json:"name"`
+	Location string            `json:"location"`
+	Version  string            `json:"version"`
+	Pokemon  []encounterDoc    `json:"pokemon"`
+	Methods  []encounterMethod `json:"methods"`
+}
+
+type statDoc struct {
+	Name   string `json:"name"`
+	Base   int    `json:"base"`
+	Actual *int   `json:"actual"`
+	IV     *int   `json:"iv"`
+	EV     *int   `json:"ev"`
+}
+
+type ownedDoc struct {
+	ID     int    `json:"id"`
+	Level  int    `json:"level"`
+	Nature string `json:"nature"`
+	Gender string `json:"gender"`
+}
+
+type inspectDoc struct {
+	ID             int       `json:"id"`
+	Name           string    `json:"name"`
+	Height         int       `json:"height"`
+	Weight         int       `json:"weight"`
+	BaseExperience int       `json:"base_experience"`
+	Owned          *ownedDoc `json:"owned"`
+	Stats          []statDoc `json:"stats"`
+	Types          []string  `json:"types"`
+}
+
+type pokedexEntryDoc struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Types []string `json:"types"`
+}
+
+type pokedexDoc struct {
+	Count int `json:"count"`
+	// Matches is how many Pokemon passed the filters, before paging.
+	Matches int               `json:"matches"`
+	Pokemon []pokedexEntryDoc `json:"pokemon"`
+}
+
+type catchDoc struct {
+	Pokemon string    `json:"pokemon"`
+	Caught  bool      `json:"caught"`
+	Owned   *ownedDoc `json:"owned"`
+}
+
+type abilitySlotDoc struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden"`
+}
+
+type infoDoc struct {
+	ID             int              `json:"id"`
+	Name           string           `json:"name"`
+	Genus          string           `json:"genus"`
+	Types          []string         `json:"types"`
+	Height         float64          `json:"height_m"`
+	Weight         float64          `json:"weight_kg"`
+	BaseExperience int              `json:"base_experience"`
+	Abilities      []abilitySlotDoc `json:"abilities"`
+	Stats          []statDoc        `json:"stats"`
+	Total          int              `json:"total"`
+	HeldItems      []heldItem       `json:"held_items"`
+	Forms          []string         `json:"forms"`
+	FlavorText     string           `json:"flavor_text"`
+}
+
+type memberDoc struct {
+	Slot    int    `json:"slot"`
+	ID      int    `json:"id"`
+	Species string `json:"species"`
+	Level   int    `json:"level"`
+	// HP and MaxHP are null when the species isn't in the Pokedex.
+	HP     *int   `json:"hp"`
+	MaxHP  *int   `json:"max_hp"`
+	Status string `json:"status"`
+}
+
+type boxDoc struct {
+	Number  int         `json:"number"`
+	Name    string      `json:"name"`
+	Count   int         `json:"count"`
+	Pokemon []memberDoc `json:"pokemon"`
+}
+
+type bagDoc struct {
+	Money int        `json:"money"`
+	Items []*bagItem `json:"items"`
+}
+
+type learnsetDoc struct {
+	Pokemon      string          `json:"pokemon"`
+	VersionGroup string          `json:"version_group"`
+	Moves        []learnsetEntry `json:"moves"`
+}
+
+type abilityDoc struct {
+	Name       string           `json:"name"`
+	Generation string           `json:"generation"`
+	Effect     string           `json:"effect"`
+	Pokemon    []abilitySlotDoc `json:"pokemon"`
+	Changes    []abilityChange  `json:"changes"`
+	History    []pastAbility    `json:"history"`
+}
+
+type effectivenessDoc struct {
+	Type       string  `json:"type"`
+	Multiplier float64 `json:"multiplier"`
+}
+
+type matchupDoc struct {
+	Attack     string             `json:"attack"`
+	Defenders  []effectivenessDoc `json:"defenders"`
+	Multiplier float64            `json:"multiplier"`
+}
+
+type weaknessDoc struct {
+	Name  string             `json:"name"`
+	Types []string           `json:"types"`
+	Takes []effectivenessDoc `json:"takes"`
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func newLocationPageDoc(list LocationAreaList) locationPageDoc {
+	doc := locationPageDoc{
+		Count:    list.Count,
+		Next:     optionalString(list.Next),
+		Previous: optionalString(list.Previous),
+		Results:  []namedResource{},
+	}
+	for _, r := range list.Results {
+		doc.Results = append(doc.Results, namedResource{Name: r.Name, URL: r.URL})
+	}
+	return doc
+}
+
+func newExploreDoc(area LocationDetails, version string, encounters []areaEncounter) exploreDoc {
+	doc := exploreDoc{Name: area.Name, Location: area.Location.Name, Version: version, Pokemon: []encounterDoc{}}
+	for _, e := range encounters {
+		doc.Pokemon = append(doc.Pokemon, encounterDoc{Name: e.Name, URL: e.URL, Chance: e.Chance, MinLevel: e.MinLevel, MaxLevel: e.MaxLevel})
+	}
+	return doc
+}
+
+// newMemberDoc describes an owned Pokemon in a party or box slot. HP is
+// only known once the species is in the Pokedex.
+func newMemberDoc(slot int, p *ownedPokemon) memberDoc {
+	doc := memberDoc{Slot: slot, ID: p.ID, Species: p.Species, Level: p.Level, Status: p.Status}
+	if species, ok := pokedex[p.Species]; ok {
+		maxHP := p.computedStats(species)[stats.HP]
+		hp := maxHP - p.Damage
+		doc.HP, doc.MaxHP = &hp, &maxHP
+	}
+	return doc
+}
+
+func newInspectDoc(p Pokemon, instance *ownedPokemon) inspectDoc {
+	doc := inspectDoc{
+		ID:             p.ID,
+		Name:           p.Name,
+		Height:         p.Height,
+		Weight:         p.Weight,
+		BaseExperience: p.BaseExperience,
+		Stats:          []statDoc{},
+		Types:          typeNames(p),
+	}
+	var computed stats.Spread
+	if instance != nil {
+		doc.Owned = &ownedDoc{ID: instance.ID, Level: instance.Level, Nature: instance.Nature, Gender: instance.Gender}
+		computed = instance.computedStats(p)
+	}
+	for _, stat := range p.Stats {
+		entry := statDoc{Name: stat.Stat.Name, Base: stat.BaseStat}
+		if s, known := stats.ParseStat(stat.Stat.Name); known && instance != nil {
+			actual, iv, ev := computed[s], instance.IVs[s], instance.EVs[s]
+			entry.Actual, entry.IV, entry.EV = &actual, &iv, &ev
+		}
+		doc.Stats = append(doc.Stats, entry)
+	}
+	return doc
+}
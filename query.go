@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+var pokemonNumberKeys = []string{"id", "base_experience", "height", "weight", "total", "hp", "attack", "defense", "special-attack", "special-defense", "speed"}
+
+// pokemonNumber returns the numeric field called key, for --sort and
+// --where: a Pokemon attribute, the base stat total or a base stat.
+func pokemonNumber(key string) (func(p Pokemon) int, bool) {
+	switch key {
+	case "id":
+		return func(p Pokemon) int { return p.ID }, true
+	case "base_experience":
+		return func(p Pokemon) int { return p.BaseExperience }, true
+	case "height":
+		return func(p Pokemon) int { return p.Height }, true
+	case "weight":
+		return func(p Pokemon) int { return p.Weight }, true
+	case "total":
+		return func(p Pokemon) int { return baseStats(p).Total() }, true
+	}
+	stat, ok := stats.ParseStat(strings.ReplaceAll(key, "_", "-"))
+	if !ok {
+		return nil, false
+	}
+	return func(p Pokemon) int { return baseStats(p)[stat] }, true
+}
+
+type pokemonFilter func(p Pokemon) bool
+
+func hasType(p Pokemon, name string) bool {
+	return slices.Contains(typeNames(p), name)
+}
+
+func hasAbility(p Pokemon, name string) bool {
+	for _, a := range p.Abilities {
+		if a.Ability.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasMove(p Pokemon, name string) bool {
+	for _, m := range p.Moves {
+		if m.Move.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parseQuery parses a --where expression: conditions joined by "and" and
+// "or", where "and" binds tighter. A condition compares a field with a
+// number, as in base_experience>200 or speed <= 50, tests the name with =
+// or !=, or is one of has_type:, has_ability: and has_move:.
+func parseQuery(query string) (pokemonFilter, error) {
+	var groups [][]pokemonFilter
+	var group []pokemonFilter
+	var words []string
+	flush := func() error {
+		if len(words) == 0 {
+			return fmt.Errorf("incomplete condition in %q", query)
+		}
+		filter, err := parseCondition(strings.Join(words, ""))
+		if err != nil {
+			return err
+		}
+		group = append(group, filter)
+		words = nil
+		return nil
+	}
+	for _, word := range strings.Fields(strings.ToLower(query)) {
+		switch word {
+		case "and", "&&":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		case "or", "||":
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			groups = append(groups, group)
+			group = nil
+		default:
+			words = append(words, word)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	groups = append(groups, group)
+	return func(p Pokemon) bool {
+		for _, g := range groups {
+			matched := true
+			for _, filter := range g {
+				if !filter(p) {
+					matched = false
+					break
+				}
+			}
+			if matched {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+var comparisons = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+func parseCondition(cond string) (pokemonFilter, error) {
+	if key, value, ok := strings.Cut(cond, ":"); ok {
+		var has func(p Pokemon, name string) bool
+		switch key {
+		case "has_type", "type":
+			has = hasType
+		case "has_ability", "ability":
+			has = hasAbility
+		case "has_move", "move":
+			has = hasMove
+		default:
+			return nil, fmt.Errorf("unknown condition %s: (use has_type:, has_ability: or has_move:)", key)
+		}
+		return func(p Pokemon) bool { return has(p, value) }, nil
+	}
+
+	i := strings.IndexAny(cond, "<>=!")
+	if i <= 0 {
+		return nil, fmt.Errorf("can't understand %q; expected a comparison such as weight>100", cond)
+	}
+	var op string
+	for _, c := range comparisons {
+		if strings.HasPrefix(cond[i:], c) {
+			op = c
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("can't understand %q; expected a comparison such as weight>100", cond)
+	}
+	field, value := cond[:i], cond[i+len(op):]
+	if field == "name" {
+		switch op {
+		case "=", "==":
+			return func(p Pokemon) bool { return p.Name == value }, nil
+		case "!=":
+			return func(p Pokemon) bool { return p.Name != value }, nil
+		}
+		return nil, fmt.Errorf("name can only be compared with = or !=")
+	}
+	number, ok := pokemonNumber(field)
+	if !ok {
+		return nil, fmt.Errorf("unknown field %s (use name, %s)", field, strings.Join(pokemonNumberKeys, ", "))
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("%s must be compared with a number, not %q", field, value)
+	}
+	return func(p Pokemon) bool {
+		v := number(p)
+		switch op {
+		case ">=":
+			return v >= n
+		case "<=":
+			return v <= n
+		case "!=":
+			return v != n
+		case ">":
+			return v > n
+		case "<":
+			return v < n
+		}
+		return v == n
+	}, nil
+}
+
+// filterPokedex keeps the species matching every filter.
+func filterPokedex(list []Pokemon, filters ...pokemonFilter) []Pokemon {
+	var matches []Pokemon
+	for _, p := range list {
+		keep := true
+		for _, filter := range filters {
+			if !filter(p) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			matches = append(matches, p)
+		}
+	}
+	return matches
+}
+
+// pageBounds returns the slice of n results shown on a page of size limit,
+// counting pages from 1.
+func pageBounds(n, limit, page int) (int, int) {
+	start := min(n, (page-1)*limit)
+	return start, min(n, start+limit)
+}
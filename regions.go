@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/render"
+)
+
+func commandRegions(config *commandConfig, args []string) error {
+	names, err := config.resourceNames(regionNames)
+	if err != nil {
+		return err
+	}
+	if config.structuredOutput() {
+		return config.emit(struct {
+			Regions []string `json:"regions"`
+		}{names})
+	}
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+func commandLocations(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("locations command requires a region name")
+	}
+	region, err := getRegion(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(regionNames, args[0], err)
+	}
+	if config.structuredOutput() {
+		return config.emit(struct {
+			Region    string          `json:"region"`
+			Locations []namedResource `json:"locations"`
+		}{region.Name, region.Locations})
+	}
+	fmt.Println(config.t("regions.locations", displayName(region.Names.in(config.language()), region.Name)))
+	for _, l := range region.Locations {
+		fmt.Printf(" - %s\n", l.Name)
+	}
+	return nil
+}
+
+func commandAreas(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("areas command requires a location name")
+	}
+	location, err := getLocation(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(locationNames, args[0], err)
+	}
+	if config.structuredOutput() {
+		return config.emit(struct {
+			Location string          `json:"location"`
+			Region   string          `json:"region"`
+			Areas    []namedResource `json:"areas"`
+		}{location.Name, location.Region.Name, location.Areas})
+	}
+	fmt.Printf("%s (%s):\n", displayName(location.Names.in(config.language()), location.Name), location.Region.Name)
+	if len(location.Areas) == 0 {
+		fmt.Println(config.t("regions.no_areas"))
+		return nil
+	}
+	for _, a := range location.Areas {
+		fmt.Printf(" - %s\n", a.Name)
+	}
+	return nil
+}
+
+type habitat struct {
+	Area     string   `json:"area"`
+	Method   string   `json:"method"`
+	Versions []string `json:"versions"`
+	MinLevel int      `json:"min_level"`
+	MaxLevel int      `json:"max_level"`
+	Chance   int      `json:"chance"`
+}
+
+// habitats lists where p can be found in the wild, one entry per area and
+// encounter method, limited to version when it is set. Chances are summed
+// within a version like encounterMethods does, keeping the best version.
+func (config *commandConfig) habitats(p Pokemon, version string) ([]habitat, error) {
+	encounters, err := getPokemonEncounters(p, config.cache)
+	if err != nil {
+		return nil, err
+	}
+	var list []habitat
+	for _, e := range encounters {
+		byMethod := map[string]int{}
+		for _, vd := range e.VersionDetails {
+			if version != "" && vd.Version.Name != version {
+				continue
+			}
+			chances := map[string]int{}
+			for _, d := range vd.EncounterDetails {
+				i, ok := byMethod[d.Method.Name]
+				if !ok {
+					i = len(list)
+					byMethod[d.Method.Name] = i
+					list = append(list, habitat{Area: e.LocationArea.Name, Method: d.Method.Name, MinLevel: d.MinLevel})
+				}
+				h := &list[i]
+				if !slices.Contains(h.Versions, vd.Version.Name) {
+					h.Versions = append(h.Versions, vd.Version.Name)
+				}
+				h.MinLevel = min(h.MinLevel, d.MinLevel)
+				h.MaxLevel = max(h.MaxLevel, d.MaxLevel)
+				chances[d.Method.Name] += d.Chance
+			}
+			for method, chance := range chances {
+				h := &list[byMethod[method]]
+				h.Chance = max(h.Chance, chance)
+			}
+		}
+	}
+	return list, nil
+}
+
+// bestHabitat picks the area where p is most likely to turn up.
+func bestHabitat(list []habitat) habitat {
+	best := list[0]
+	for _, h := range list[1:] {
+		if h.Chance > best.Chance {
+			best = h
+		}
+	}
+	return best
+}
+
+// whereVersion is the version to search: --version when given, otherwise
+// the session version.
+func (config *commandConfig) whereVersion() string {
+	if v := config.flags["version"]; v != "" {
+		return v
+	}
+	return config.version
+}
+
+func (config *commandConfig) findHabitats(name string) (Pokemon, []habitat, error) {
+	p, err := getPokemon(name, config.cache)
+	if err != nil {
+		return p, nil, config.withSuggestions(pokemonNames, name, err)
+	}
+	list, err := config.habitats(p, config.whereVersion())
+	if err != nil {
+		return p, nil, err
+	}
+	if len(list) == 0 {
+		if v := config.whereVersion(); v != "" {
+			return p, nil, fmt.Errorf("%s can't be found in the wild in %s", p.Name, v)
+		}
+		return p, nil, fmt.Errorf("%s can't be found in the wild", p.Name)
+	}
+	return p, list, nil
+}
+
+func commandWhere(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("where command requires a pokemon name")
+	}
+	p, list, err := config.findHabitats(args[0])
+	if err != nil {
+		return err
+	}
+	if config.structuredOutput() {
+		return config.emit(struct {
+			Pokemon  string    `json:"pokemon"`
+			Version  string    `json:"version"`
+			Habitats []habitat `json:"habitats"`
+		}{p.Name, config.whereVersion(), list})
+	}
+	fmt.Println(config.t("where.title", config.pokemonDisplayName(p)))
+	table := render.Table{
+		Headers: []string{config.t("column.area"), config.t("column.method"), config.t("column.levels"), config.t("column.chance"), config.t("column.versions")},
+		Right:   []bool{false, false, true, true},
+	}
+	for _, h := range list {
+		levels := fmt.Sprintf("%d-%d", h.MinLevel, h.MaxLevel)
+		if h.MinLevel == h.MaxLevel {
+			levels = fmt.Sprint(h.MinLevel)
+		}
+		table.Append(h.Area, h.Method, levels, fmt.Sprintf("%d%%", h.Chance), strings.Join(h.Versions, ", "))
+	}
+	if err := table.Render(os.Stdout, config.style); err != nil {
+		return err
+	}
+	fmt.Println(config.t("where.goto", p.Name, bestHabitat(list).Area))
+	return nil
+}
+
+func commandGoto(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("goto command requires a pokemon name")
+	}
+	_, list, err := config.findHabitats(args[0])
+	if err != nil {
+		return err
+	}
+	return commandExplore(config, []string{bestHabitat(list).Area})
+}
@@ -1,9 +1,83 @@
 package main
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
-func cleanInput(text string) []string {
-	output := strings.ToLower(text)
-	words := strings.Fields(output)
-	return words
+// tokenize splits a command line on whitespace. Single or double quotes
+// group words into one argument and a backslash escapes the next character
+// outside single quotes. Case is preserved.
+func tokenize(text string) ([]string, error) {
+	commands, err := scan(text, false)
+	if err != nil || len(commands) == 0 {
+		return nil, err
+	}
+	return commands[0], nil
+}
+
+// splitCommands tokenizes text like tokenize, and also starts a new command
+// at every ; that isn't quoted or escaped.
+func splitCommands(text string) ([][]string, error) {
+	return scan(text, true)
+}
+
+func scan(text string, semicolons bool) ([][]string, error) {
+	var commands [][]string
+	var tokens []string
+	var current strings.Builder
+	inToken := false
+	var quote rune
+	escaped := false
+	endToken := func() {
+		if inToken {
+			tokens = append(tokens, current.String())
+			current.Reset()
+			inToken = false
+		}
+	}
+	for _, r := range text {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inToken = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			endToken()
+		case r == ';' && semicolons:
+			endToken()
+			commands = append(commands, tokens)
+			tokens = nil
+		default:
+			current.WriteRune(r)
+			inToken = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if escaped {
+		return nil, fmt.Errorf("nothing to escape at end of line")
+	}
+	endToken()
+	return append(commands, tokens), nil
+}
+
+// quoteToken is the inverse of tokenize for a single argument.
+func quoteToken(token string) string {
+	if token != "" && !strings.ContainsAny(token, " \t\"'\\;") {
+		return token
+	}
+	return "'" + strings.ReplaceAll(token, "'", `'\''`) + "'"
 }
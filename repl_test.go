@@ -1,52 +1,32 @@
 package main
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
+	"math/rand"
 	"os"
+	"reflect"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/KindMinotaur/pokedexcli/internal/pokecache"
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+	"github.com/KindMinotaur/pokedexcli/internal/types"
 )
 
-func TestCleanInput(t *testing.T) {
-	cases := []struct {
-		input    string
-		expected []string
-	}{
-		{
-			input:    "  hello  world  ",
-			expected: []string{"hello", "world"},
-		},
-		{
-			input:    "Charmander BulbasaUr PIKACHU  ",
-			expected: []string{"charmander", "bulbasaur", "pikachu"},
-		},
-		{
-			input:    "TeSts  ArE LaMe  ",
-			expected: []string{"tests", "are", "lame"},
-		},
-	}
-
-	for _, c := range cases {
-		actual := cleanInput(c.input)
-		for i := range actual {
-			word := actual[i]
-			expectedWord := c.expected[i]
-			if word != expectedWord {
-				t.Errorf("Cleaning needed...")
-			}
-		}
-	}
-}
-
 func TestCommandCatch(t *testing.T) {
-	pokedex = make(map[string]Pokemon)
+	newGame()
 
 	cache := pokecache.NewCache(5 * time.Minute)
 	pikachuJSON := `{"id":25,"name":"pikachu","base_experience":112,"height":4,"weight":60}`
 	cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte(pikachuJSON))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/pikachu", []byte(`{"name":"pikachu","gender_rate":4}`))
 
 	config := &commandConfig{
 		cache: cache,
@@ -57,14 +37,25 @@ func TestCommandCatch(t *testing.T) {
 		t.Errorf("Expected error for no args")
 	}
 
+	bag = map[string]*bagItem{"poke-ball": {Name: "poke-ball", Pocket: "pokeballs", Quantity: 2}}
 	err = commandCatch(config, []string{"pikachu"})
 	if err != nil {
 		t.Errorf("Unexpected error: %v", err)
 	}
+	if bag["poke-ball"].Quantity != 1 {
+		t.Errorf("Expected a poke-ball to be used up, got %d left", bag["poke-ball"].Quantity)
+	}
+	if err := commandCatch(config, []string{"pikachu"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := commandCatch(config, []string{"pikachu"}); err == nil || !strings.Contains(err.Error(), "don't have any poke-ball") {
+		t.Errorf("Expected an error once the poke-balls run out, got %v", err)
+	}
+	bag = newBag()
 }
 
 func TestCommandInspect(t *testing.T) {
-	pokedex = make(map[string]Pokemon)
+	newGame()
 
 	config := &commandConfig{}
 
@@ -146,13 +137,1476 @@ func TestCommandInspect(t *testing.T) {
 	if !strings.Contains(output2, "Stats:") {
 		t.Errorf("Expected 'Stats:', got %s", output2)
 	}
-	if !strings.Contains(output2, "-hp: 35") {
-		t.Errorf("Expected '-hp: 35', got %s", output2)
+	if !regexp.MustCompile(`hp\s+35\s+█`).MatchString(output2) {
+		t.Errorf("Expected an hp row with base 35 and a bar, got %s", output2)
+	}
+	if !strings.Contains(output2, "Types: electric") {
+		t.Errorf("Expected 'Types: electric', got %s", output2)
+	}
+}
+
+func TestCommandInspectOwned(t *testing.T) {
+	newGame()
+
+	var pikachu Pokemon
+	pikachuJSON := `{"name":"pikachu","height":4,"weight":60,"stats":[
+		{"base_stat":35,"effort":0,"stat":{"name":"hp"}},
+		{"base_stat":55,"effort":0,"stat":{"name":"attack"}},
+		{"base_stat":40,"effort":0,"stat":{"name":"defense"}},
+		{"base_stat":50,"effort":0,"stat":{"name":"special-attack"}},
+		{"base_stat":50,"effort":0,"stat":{"name":"special-defense"}},
+		{"base_stat":90,"effort":2,"stat":{"name":"speed"}}]}`
+	if err := json.Unmarshal([]byte(pikachuJSON), &pikachu); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex["pikachu"] = pikachu
+	owned[7] = &ownedPokemon{
+		ID:      7,
+		Species: "pikachu",
+		Level:   50,
+		Nature:  "timid",
+		IVs:     stats.Spread{31, 31, 31, 31, 31, 31},
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := commandInspect(&commandConfig{}, []string{"7"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	if !regexp.MustCompile(`hp\s+35\s+110\s+31\s+0`).MatchString(output) {
+		t.Errorf("Expected computed hp of 110, got %s", output)
+	}
+	if !regexp.MustCompile(`speed\s+90\s+121\s+31\s+0`).MatchString(output) {
+		t.Errorf("Expected timid speed of 121, got %s", output)
+	}
+	if y := effortYield(pikachu); y[stats.Speed] != 2 {
+		t.Errorf("Expected speed effort yield of 2, got %v", y)
+	}
+}
+
+func TestKnownMoves(t *testing.T) {
+	var pikachu Pokemon
+	pikachuJSON := `{"name":"pikachu","moves":[
+		{"move":{"name":"thunder-shock"},"version_group_details":[{"level_learned_at":1,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"growl"},"version_group_details":[{"level_learned_at":1,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"thunderbolt"},"version_group_details":[{"level_learned_at":0,"move_learn_method":{"name":"machine"}}]},
+		{"move":{"name":"quick-attack"},"version_group_details":[{"level_learned_at":16,"move_learn_method":{"name":"level-up"}},{"level_learned_at":6,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"thunder-wave"},"version_group_details":[{"level_learned_at":9,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"slam"},"version_group_details":[{"level_learned_at":20,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"thunder"},"version_group_details":[{"level_learned_at":43,"move_learn_method":{"name":"level-up"}}]},
+		{"move":{"name":"nuzzle"},"version_group_details":[{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"x-y"}}]}]}`
+	if err := json.Unmarshal([]byte(pikachuJSON), &pikachu); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []string{"thunder-shock", "quick-attack", "thunder-wave", "slam"}
+	actual := knownMoves(pikachu, "", 25)
+	if strings.Join(actual, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+	if actual := knownMoves(pikachu, "x-y", 25); strings.Join(actual, ",") != "nuzzle" {
+		t.Errorf("Expected only the x-y moves, got %v", actual)
+	}
+}
+
+func TestCommandWeak(t *testing.T) {
+	newGame()
+	chart := types.NewChart()
+	chart.Add("electric", types.Relations{NoDamageTo: []string{"ground"}, DoubleDamageTo: []string{"water"}})
+	chart.Add("grass", types.Relations{DoubleDamageTo: []string{"water", "ground"}})
+	chart.Add("fire", types.Relations{HalfDamageTo: []string{"water"}})
+	chart.Add("water", types.Relations{HalfDamageTo: []string{"water"}, DoubleDamageTo: []string{"ground"}})
+	chart.Add("ground", types.Relations{})
+
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/quagsire", []byte(`{"name":"quagsire","types":[{"slot":1,"type":{"name":"water"}},{"slot":2,"type":{"name":"ground"}}]}`))
+	config := &commandConfig{cache: cache, types: chart}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := commandWeak(config, []string{"quagsire"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	for _, expected := range []string{"4x: grass", "0.5x: fire", "0x: electric"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected '%s', got %s", expected, output)
+		}
+	}
+	if !strings.Contains(output, "0.5x: fire\n") {
+		t.Errorf("Expected water to be neutral, got %s", output)
+	}
+}
+
+func TestPartyAndBoxes(t *testing.T) {
+	newGame()
+
+	for i := 0; i < partySize+1; i++ {
+		registerOwned(&ownedPokemon{Species: "pidgey", Level: 5})
+	}
+	if len(party) != partySize {
+		t.Fatalf("Expected a full party, got %v", party)
+	}
+	if box, slot := boxLocation(7); box != boxes[0] || slot != 0 {
+		t.Errorf("Expected the seventh catch in box 1 slot 1")
+	}
+
+	config := &commandConfig{}
+	if err := commandWithdraw(config, []string{"7"}); err == nil {
+		t.Errorf("Expected an error withdrawing into a full party")
+	}
+	if err := commandDeposit(config, []string{"2"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := commandWithdraw(config, []string{"7"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := commandParty(config, []string{"swap", "1", "6"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	expected := []int{7, 3, 4, 5, 6, 1}
+	for i := range expected {
+		if party[i] != expected[i] {
+			t.Fatalf("Expected party %v, got %v", expected, party)
+		}
+	}
+	if err := commandBox(config, []string{"rename", "1", "Water", "types"}); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if boxes[0].Name != "Water types" {
+		t.Errorf("Expected box to be renamed, got %s", boxes[0].Name)
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{"pidgey": {Name: "pidgey"}}
+	seen = map[string]bool{"rattata": true}
+	owned = make(map[int]*ownedPokemon)
+	party = nil
+	boxes = newBoxes()
+	nextOwnedID = 1
+	registerOwned(&ownedPokemon{Species: "pidgey", Level: 5, Nature: "bold"})
+
+	path := t.TempDir() + "/save.json"
+	if err := saveGame(path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.Contains(string(data), `"pokedex":["pidgey"]`) {
+		t.Errorf("Expected only the caught species' names to be saved, got %s %v", data, err)
+	}
+
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/pidgey", []byte(`{"id":16,"name":"pidgey"}`))
+	pokedex = make(map[string]Pokemon)
+	owned = make(map[int]*ownedPokemon)
+	party = nil
+	nextOwnedID = 1
+	if err := loadGame(path, cache); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p, ok := owned[1]; !ok || p.Nature != "bold" {
+		t.Errorf("Expected pidgey to be restored, got %v", owned)
+	}
+	if len(party) != 1 || party[0] != 1 || nextOwnedID != 2 {
+		t.Errorf("Expected party and id counter to be restored, got %v %d", party, nextOwnedID)
+	}
+	if p, ok := pokedex["pidgey"]; !ok || p.ID != 16 {
+		t.Errorf("Expected pokedex to be refetched, got %v", pokedex)
+	}
+	if !seen["rattata"] {
+		t.Errorf("Expected seen species to be restored, got %v", seen)
+	}
+}
+
+func TestLoadGameOldPokedex(t *testing.T) {
+	newGame()
+	path := t.TempDir() + "/save.json"
+	if err := os.WriteFile(path, []byte(`{"pokedex":{"pidgey":{"id":16,"name":"pidgey"}}}`), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex = make(map[string]Pokemon)
+	if err := loadGame(path, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p, ok := pokedex["pidgey"]; !ok || p.ID != 16 {
+		t.Errorf("Expected the full Pokemon data of an old save to be used, got %v", pokedex)
+	}
+}
+
+func TestSaveOnlyAfterChanges(t *testing.T) {
+	newGame()
+	config := &commandConfig{savePath: t.TempDir() + "/save.json"}
+
+	old := os.Stdout
+	os.Stdout, _ = os.Open(os.DevNull)
+	dispatch(config, "help")
+	_, helpErr := os.Stat(config.savePath)
+	dispatch(config, "box rename 1 Grass")
+	_, renameErr := os.Stat(config.savePath)
+	os.Stdout = old
+
+	if !errors.Is(helpErr, fs.ErrNotExist) {
+		t.Errorf("Expected help not to write the save file, got %v", helpErr)
+	}
+	if renameErr != nil {
+		t.Errorf("Expected renaming a box to save, got %v", renameErr)
+	}
+	boxes = newBoxes()
+}
+
+func TestLoadGameDropsMissingIDs(t *testing.T) {
+	newGame()
+	path := t.TempDir() + "/save.json"
+	data := `{"owned":[{"id":1,"species":"pidgey","level":5}],"next_owned_id":2,"party":[7,1],
+		"boxes":[{"name":"box 1","slots":[9,1]}]}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex = map[string]Pokemon{"pidgey": {Name: "pidgey"}}
+	if err := loadGame(path, nil); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(party) != 1 || party[0] != 1 {
+		t.Errorf("Expected the missing party id to be dropped, got %v", party)
+	}
+	if boxes[0].Slots[0] != 0 || boxes[0].Slots[1] != 1 {
+		t.Errorf("Expected the missing box id to be cleared, got %v", boxes[0].Slots[:2])
+	}
+
+	party = []int{7}
+	if _, ok := leadPokemon(); ok {
+		t.Errorf("Expected no lead for a party id that isn't owned")
+	}
+	party = nil
+	boxes = newBoxes()
+}
+
+func TestCommandEvolve(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{"machop": {Name: "machop"}}
+	owned = make(map[int]*ownedPokemon)
+	party = nil
+	boxes = newBoxes()
+	nextOwnedID = 1
+	machop := registerOwned(&ownedPokemon{Species: "machop", Level: 27})
+
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/machop", []byte(`{"name":"machop","evolution_chain":{"url":"https://pokeapi.co/api/v2/evolution-chain/30/"}}`))
+	cache.Add("https://pokeapi.co/api/v2/evolution-chain/30/", []byte(`{"id":30,"chain":{"species":{"name":"machop"},"evolves_to":[{"species":{"name":"machoke"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":28}]}]}}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/machoke", []byte(`{"name":"machoke","species":{"name":"machoke"}}`))
+	config := &commandConfig{cache: cache}
+
+	if err := commandEvolve(config, []string{"1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if machop.Species != "machop" {
+		t.Fatalf("Expected machop to not evolve before level 28")
+	}
+
+	machop.Level = 28
+	if err := commandEvolve(config, []string{"1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if machop.Species != "machoke" {
+		t.Errorf("Expected machop to evolve into machoke, got %s", machop.Species)
+	}
+	if _, ok := pokedex["machoke"]; !ok {
+		t.Errorf("Expected machoke to be registered in the pokedex")
+	}
+}
+
+func TestEvolveTradeAndGender(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{}
+	owned = make(map[int]*ownedPokemon)
+	party = nil
+	boxes = newBoxes()
+	bag = newBag()
+	nextOwnedID = 1
+	slowpoke := registerOwned(&ownedPokemon{Species: "slowpoke", Level: 30})
+	karrablast := registerOwned(&ownedPokemon{Species: "karrablast", Level: 30})
+	combee := registerOwned(&ownedPokemon{Species: "combee", Level: 20, Gender: "male"})
+
+	cache := pokecache.NewCache(5 * time.Minute)
+	for name, chain := range map[string]string{
+		"slowpoke":   `{"species":{"name":"slowpoke"},"evolves_to":[{"species":{"name":"slowking"},"evolution_details":[{"trigger":{"name":"trade"},"held_item":{"name":"kings-rock"}}]}]}`,
+		"karrablast": `{"species":{"name":"karrablast"},"evolves_to":[{"species":{"name":"escavalier"},"evolution_details":[{"trigger":{"name":"trade"},"trade_species":{"name":"shelmet"}}]}]}`,
+		"combee":     `{"species":{"name":"combee"},"evolves_to":[{"species":{"name":"vespiquen"},"evolution_details":[{"trigger":{"name":"level-up"},"min_level":21,"gender":1}]}]}`,
+	} {
+		url := "https://pokeapi.co/api/v2/evolution-chain/" + name + "/"
+		cache.Add("https://pokeapi.co/api/v2/pokemon-species/"+name, []byte(`{"name":"`+name+`","evolution_chain":{"url":"`+url+`"}}`))
+		cache.Add(url, []byte(`{"chain":`+chain+`}`))
+	}
+	for _, name := range []string{"slowking", "escavalier", "vespiquen"} {
+		cache.Add("https://pokeapi.co/api/v2/pokemon/"+name, []byte(`{"name":"`+name+`","species":{"name":"`+name+`"}}`))
+	}
+	config := &commandConfig{cache: cache}
+
+	old := os.Stdout
+	_, w, _ := os.Pipe()
+	os.Stdout = w
+	defer func() { os.Stdout = old }()
+
+	if err := commandEvolve(config, []string{"1", "trade"}); err != nil || slowpoke.Species != "slowpoke" {
+		t.Errorf("Expected slowpoke to need a king's rock, got %s (%v)", slowpoke.Species, err)
+	}
+	addToBag("kings-rock", "held-items", 1)
+	if err := commandGive(config, []string{"kings-rock", "1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := commandEvolve(config, []string{"1", "trade"}); err != nil || slowpoke.Species != "slowking" {
+		t.Errorf("Expected slowpoke to evolve into slowking, got %s (%v)", slowpoke.Species, err)
+	}
+	if slowpoke.HeldItem != "" {
+		t.Errorf("Expected the king's rock to be used up, still holding %q", slowpoke.HeldItem)
+	}
+
+	if err := commandEvolve(config, []string{"2", "trade", "pikachu"}); err != nil || karrablast.Species != "karrablast" {
+		t.Errorf("Expected karrablast to need a shelmet, got %s (%v)", karrablast.Species, err)
+	}
+	if err := commandEvolve(config, []string{"2", "trade", "shelmet"}); err != nil || karrablast.Species != "escavalier" {
+		t.Errorf("Expected karrablast to evolve into escavalier, got %s (%v)", karrablast.Species, err)
+	}
+
+	combee.Level = 21
+	if err := commandEvolve(config, []string{"3"}); err != nil || combee.Species != "combee" {
+		t.Errorf("Expected a male combee not to evolve, got %s (%v)", combee.Species, err)
+	}
+	combee.Gender = "female"
+	asked := ""
+	config.confirm = func(question string) bool {
+		asked = question
+		return true
+	}
+	checkEvolution(config, combee)
+	if asked == "" || combee.Species != "vespiquen" {
+		t.Errorf("Expected to be asked and evolve a female combee, got %s after %q", combee.Species, asked)
+	}
+}
+
+func TestCommandInfo(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/gengar", []byte(`{"id":94,"name":"gengar","height":15,"weight":405,
+		"species":{"name":"gengar"},
+		"types":[{"slot":1,"type":{"name":"ghost"}},{"slot":2,"type":{"name":"poison"}}],
+		"abilities":[{"is_hidden":false,"slot":1,"ability":{"name":"cursed-body"}}],
+		"stats":[{"base_stat":60,"stat":{"name":"hp"}},{"base_stat":130,"stat":{"name":"special-attack"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/gengar", []byte(`{"name":"gengar",
+		"flavor_text_entries":[{"flavor_text":"Under a full moon,\nthis POKéMON\flikes to mimic","language":{"name":"en"}}],
+		"genera":[{"genus":"Shadow Pokémon","language":{"name":"en"}}]}`))
+	config := &commandConfig{cache: cache}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := commandInfo(config, []string{"gengar"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	for _, expected := range []string{"#94 gengar - the Shadow Pokémon", "Types: ghost/poison", "Height: 1.5 m", "Weight: 40.5 kg", "-cursed-body\n", "total: 190", "Under a full moon, this POKéMON likes to mimic"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected '%s', got %s", expected, output)
+		}
+	}
+}
+
+func TestCommandInfoForms(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/rotom", []byte(`{"id":479,"name":"rotom","species":{"name":"rotom"},
+		"forms":[{"name":"rotom"}],
+		"types":[{"slot":1,"type":{"name":"electric"}},{"slot":2,"type":{"name":"ghost"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/rotom", []byte(`{"name":"rotom","varieties":[
+		{"is_default":true,"pokemon":{"name":"rotom"}},
+		{"is_default":false,"pokemon":{"name":"rotom-heat"}},
+		{"is_default":false,"pokemon":{"name":"rotom-wash"}}]}`))
+	config := &commandConfig{cache: cache}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	err := commandInfo(config, []string{"rotom"})
+	if err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	for _, expected := range []string{"Forms:", "-rotom\n", "-rotom-heat\n", "-rotom-wash\n"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected '%s', got %s", expected, output)
+		}
+	}
+}
+
+func TestLearnset(t *testing.T) {
+	var bulbasaur Pokemon
+	bulbasaurJSON := `{"name":"bulbasaur","moves":[
+		{"move":{"name":"tackle"},"version_group_details":[
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"red-blue","url":"https://pokeapi.co/api/v2/version-group/1/"}},
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}]},
+		{"move":{"name":"vine-whip"},"version_group_details":[
+			{"level_learned_at":3,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}]},
+		{"move":{"name":"growl"},"version_group_details":[
+			{"level_learned_at":1,"move_learn_method":{"name":"level-up"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}]},
+		{"move":{"name":"solar-beam"},"version_group_details":[
+			{"level_learned_at":0,"move_learn_method":{"name":"machine"},"version_group":{"name":"scarlet-violet","url":"https://pokeapi.co/api/v2/version-group/25/"}}]}]}`
+	if err := json.Unmarshal([]byte(bulbasaurJSON), &bulbasaur); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if vg := latestVersionGroup(bulbasaur); vg != "scarlet-violet" {
+		t.Errorf("Expected scarlet-violet, got %s", vg)
+	}
+	expected := []learnsetEntry{
+		{Move: "growl", Method: "level-up", Level: 1},
+		{Move: "tackle", Method: "level-up", Level: 1},
+		{Move: "vine-whip", Method: "level-up", Level: 3},
+		{Move: "solar-beam", Method: "machine", Level: 0},
+	}
+	actual := learnset(bulbasaur, "scarlet-violet", "")
+	if fmt.Sprint(actual) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, actual)
+	}
+	if actual := learnset(bulbasaur, "red-blue", "machine"); len(actual) != 0 {
+		t.Errorf("Expected no red-blue machine moves, got %v", actual)
+	}
+}
+
+func TestCommandAbility(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/ability/stench", []byte(`{"name":"stench","generation":{"name":"generation-iii"},
+		"effect_entries":[{"effect":"May cause the target to flinch.","language":{"name":"en"}}],
+		"effect_changes":[{"version_group":{"name":"black-white"},"effect_entries":[
+			{"effect":"Has no effect\nin battle.","language":{"name":"en"}},{"effect":"Aucun effet.","language":{"name":"fr"}}]}],
+		"pokemon":[{"is_hidden":false,"pokemon":{"name":"grimer"}},{"is_hidden":true,"pokemon":{"name":"gloom"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/grimer", []byte(`{"name":"grimer",
+		"abilities":[{"is_hidden":false,"slot":1,"ability":{"name":"stench"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/gloom", []byte(`{"name":"gloom",
+		"abilities":[{"is_hidden":false,"slot":1,"ability":{"name":"chlorophyll"}},{"is_hidden":true,"slot":3,"ability":{"name":"stench"}}],
+		"past_abilities":[{"generation":{"name":"generation-iv"},"abilities":[{"ability":null,"is_hidden":true,"slot":3}]}]}`))
+	config := &commandConfig{cache: cache}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := commandAbility(config, []string{"stench"})
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	for _, want := range []string{
+		"  -gloom (hidden)\n",
+		"Effect changes:\n  -before black-white: Has no effect in battle.\n",
+		"Past abilities:\n  up to generation-iv:\n    -gloom slot 3: no ability (now stench)\n",
+	} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("Expected %q in output, got %s", want, out)
+		}
+	}
+}
+
+func TestGenerationNumber(t *testing.T) {
+	for name, want := range map[string]int{"generation-i": 1, "generation-iv": 4, "generation-viii": 8, "generation-ix": 9, "kanto": 0} {
+		if got := generationNumber(name); got != want {
+			t.Errorf("generationNumber(%s) = %d, want %d", name, got, want)
+		}
+	}
+}
+
+func TestCommandUse(t *testing.T) {
+	newGame()
+	var pidgey Pokemon
+	if err := json.Unmarshal([]byte(`{"name":"pidgey","stats":[{"base_stat":40,"stat":{"name":"hp"}}]}`), &pidgey); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex = map[string]Pokemon{"pidgey": pidgey}
+	owned = make(map[int]*ownedPokemon)
+	party = nil
+	boxes = newBoxes()
+	bag = newBag()
+	nextOwnedID = 1
+	p := registerOwned(&ownedPokemon{Species: "pidgey", Level: 50, Damage: 30, Status: "poison"})
+	maxHP := p.computedStats(pidgey)[stats.HP]
+
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/pidgey", []byte(`{"name":"pidgey"}`))
+	config := &commandConfig{cache: cache}
+	if err := commandUse(config, []string{"potion", "1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Damage != 10 || p.Status != "poison" {
+		t.Errorf("Expected potion to heal 20 HP and leave the poison, got damage %d status %q", p.Damage, p.Status)
+	}
+	if bag["potion"].Quantity != 2 {
+		t.Errorf("Expected a potion to be used up, have %d", bag["potion"].Quantity)
+	}
+
+	addToBag("revive", "medicine", 1)
+	if err := commandUse(config, []string{"revive", "1"}); err == nil {
+		t.Errorf("Expected revive to have no effect on a conscious pokemon")
+	}
+	p.Damage = maxHP
+	if err := commandUse(config, []string{"revive", "1"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if p.Damage != maxHP-maxHP/2 || p.Status != "" {
+		t.Errorf("Expected revive to restore half HP, got damage %d of %d", p.Damage, maxHP)
+	}
+	if _, ok := bag["revive"]; ok {
+		t.Errorf("Expected the last revive to leave the bag")
+	}
+
+	if err := commandUse(config, []string{"poke-ball"}); err == nil {
+		t.Errorf("Expected an error throwing a ball outside of battle")
+	}
+}
+
+func TestWithSuggestions(t *testing.T) {
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon?limit=1000", []byte(`{"next":"https://pokeapi.co/api/v2/pokemon?offset=1000&limit=1000","results":[{"name":"pikachu"},{"name":"raichu"}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon?offset=1000&limit=1000", []byte(`{"next":null,"results":[{"name":"pichu"}]}`))
+	config := &commandConfig{cache: cache}
+
+	err := config.withSuggestions(pokemonNames, "pikachoo", &notFoundError{url: "https://pokeapi.co/api/v2/pokemon/pikachoo"})
+	if err == nil || !strings.Contains(err.Error(), "Did you mean pikachu?") {
+		t.Errorf("Expected a suggestion for pikachu, got %v", err)
+	}
+	if names := config.names[pokemonNames]; len(names) != 3 {
+		t.Errorf("Expected both pages to be indexed, got %v", names)
+	}
+
+	other := fmt.Errorf("connection refused")
+	if err := config.withSuggestions(pokemonNames, "pikachoo", other); err != other {
+		t.Errorf("Expected other errors to pass through, got %v", err)
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	newGame()
+	owned = map[int]*ownedPokemon{3: {ID: 3, Species: "pikachu"}}
+	var area LocationDetails
+	if err := json.Unmarshal([]byte(`{"pokemon_encounters":[{"pokemon":{"name":"pidgey"}},{"pokemon":{"name":"pikachu"}},{"pokemon":{"name":"rattata"}}]}`), &area); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config := &commandConfig{area: &area}
+	complete := completer(config)
+
+	cases := []struct {
+		head     string
+		word     string
+		expected []string
+	}{
+		{head: "", word: "ex", expected: []string{"exit", "explore"}},
+		{head: "catch ", word: "pi", expected: []string{"pidgey", "pikachu"}},
+		{head: "inspect ", word: "", expected: []string{"pikachu", "3"}},
+		{head: "catch pikachu ", word: "", expected: nil},
+	}
+	for _, c := range cases {
+		actual := complete(c.head, c.word)
+		if fmt.Sprint(actual) != fmt.Sprint(c.expected) {
+			t.Errorf("complete(%q, %q): expected %v, got %v", c.head, c.word, c.expected, actual)
+		}
+	}
+}
+
+func TestExpandHistory(t *testing.T) {
+	entries := []historyEntry{{Line: "map"}, {Line: "explore eterna-city-area"}, {Line: "catch"}}
+	cases := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{input: "map", expected: "map"},
+		{input: "!!", expected: "catch"},
+		{input: "!2", expected: "explore eterna-city-area"},
+		{input: "!-3", expected: "map"},
+		{input: "!3 pikachu", expected: "catch pikachu"},
+		{input: "!4", wantErr: true},
+		{input: "!x", wantErr: true},
+	}
+	for _, c := range cases {
+		actual, _, err := expandHistory(entries, c.input)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error", c.input)
+			}
+			continue
+		}
+		if err != nil || actual != c.expected {
+			t.Errorf("%s: expected %q, got %q (%v)", c.input, c.expected, actual, err)
+		}
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := t.TempDir() + "/history"
+	config := &commandConfig{historyPath: path, historyMax: 2}
+	for _, line := range []string{"map", "mapb", "help", "pokedex", "bag"} {
+		if err := recordHistory(config, line); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if len(config.history) != 2 || config.history[1].Line != "bag" {
+		t.Errorf("Expected the last two entries in memory, got %v", config.history)
+	}
+
+	entries, lines, err := loadHistory(path, 3)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if lines > 4 {
+		t.Errorf("Expected the file to be trimmed, has %d lines", lines)
+	}
+	if len(entries) == 0 || entries[len(entries)-1].Line != "bag" || entries[0].Time.IsZero() {
+		t.Errorf("Expected timestamped entries ending with bag, got %v", entries)
+	}
+}
+
+func TestExpandAliases(t *testing.T) {
+	path := t.TempDir() + "/config.json"
+	config := &commandConfig{configPath: path}
+	config.settings, _ = loadSettings(path)
+
+	if err := commandAlias(config, []string{"ex", "explore"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	words, _ := tokenize(`hunt "ex $1; catch $2"`)
+	if err := commandMacro(config, words); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := commandAlias(config, []string{"map", "explore"}); err == nil {
+		t.Errorf("Expected an error when shadowing a command")
+	}
+
+	lines, err := expandCommand(config, []string{"hunt", "canalave-city-area", "tentacool"}, map[string]bool{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [][]string{{"explore", "canalave-city-area"}, {"catch", "tentacool"}}
+	if fmt.Sprint(lines) != fmt.Sprint(expected) {
+		t.Errorf("Expected %v, got %v", expected, lines)
+	}
+
+	for _, line := range []string{`say alias "a;b"`, `quoted 'alias say "a;b"; alias'`} {
+		words, _ := tokenize("macro " + line)
+		if err := commandMacro(config, words[1:]); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	lines, err = expandCommand(config, []string{"say"}, map[string]bool{})
+	if err != nil || fmt.Sprint(lines) != fmt.Sprint([][]string{{"alias", "a;b"}}) {
+		t.Errorf("Expected the quoted ; to stay in one argument, got %q %v", lines, err)
+	}
+	lines, err = expandCommand(config, []string{"quoted"}, map[string]bool{})
+	if err != nil || fmt.Sprint(lines) != fmt.Sprint([][]string{{"alias", "say", "a;b"}, {"alias"}}) {
+		t.Errorf("Expected only the unquoted ; to split, got %q %v", lines, err)
+	}
+	lines, err = expandCommand(config, []string{"hunt", "a;b"}, map[string]bool{})
+	if err != nil || fmt.Sprint(lines) != fmt.Sprint([][]string{{"explore", "a;b"}, {"catch"}}) {
+		t.Errorf("Expected a ; in an argument to stay literal, got %q %v", lines, err)
+	}
+
+	words, _ = tokenize(`mime inspect "mr mime"`)
+	if err := commandAlias(config, words); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	lines, err = expandCommand(config, []string{"mime"}, map[string]bool{})
+	if err != nil || fmt.Sprint(lines) != fmt.Sprint([][]string{{"inspect", "mr mime"}}) {
+		t.Errorf("Expected the quoted alias argument to stay one word, got %q %v", lines, err)
+	}
+
+	config.settings.Aliases["a"] = "b"
+	config.settings.Aliases["b"] = "a"
+	if _, err := expandCommand(config, []string{"a"}, map[string]bool{}); err == nil {
+		t.Errorf("Expected an error for a recursive alias")
+	}
+
+	saved, err := loadSettings(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if saved.Aliases["ex"] != "explore" || saved.Macros["hunt"] != "ex $1; catch $2" {
+		t.Errorf("Expected definitions to be persisted, got %+v", saved)
+	}
+}
+
+func TestTokenize(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected []string
+	}{
+		{input: `  Box rename 1  "Water types" `, expected: []string{"Box", "rename", "1", "Water types"}},
+		{input: `alias x 'say "hi"'`, expected: []string{"alias", "x", `say "hi"`}},
+		{input: `a\ b c\"d`, expected: []string{"a b", `c"d`}},
+		{input: `moves --method=egg ""`, expected: []string{"moves", "--method=egg", ""}},
+	}
+	for _, c := range cases {
+		actual, err := tokenize(c.input)
+		if err != nil || fmt.Sprintf("%q", actual) != fmt.Sprintf("%q", c.expected) {
+			t.Errorf("%s: expected %q, got %q (%v)", c.input, c.expected, actual, err)
+		}
+		if len(actual) > 0 {
+			back, _ := tokenize(quoteToken(actual[len(actual)-1]))
+			if len(back) != 1 || back[0] != actual[len(actual)-1] {
+				t.Errorf("quoteToken(%q) did not round trip: %q", actual[len(actual)-1], back)
+			}
+		}
+	}
+	if _, err := tokenize(`explore "canalave`); err == nil {
+		t.Errorf("Expected an error for an unterminated quote")
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	moves := commands["moves"]
+	if usage := moves.usage(); usage != "moves <pokemon> [-v|--version-group <name>] [-m|--method <method>]" {
+		t.Errorf("Unexpected usage: %s", usage)
+	}
+	args, flags, err := moves.parseArgs([]string{"-m", "egg", "Pikachu", "--version-group=red-blue"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(args) != 1 || args[0] != "pikachu" || flags["method"] != "egg" || flags["version-group"] != "red-blue" {
+		t.Errorf("Unexpected result: %v %v", args, flags)
+	}
+
+	args, _, err = commands["box"].parseArgs([]string{"rename", "2", "Water Types"})
+	if err != nil || args[2] != "Water Types" {
+		t.Errorf("Expected text arguments to keep their case, got %v (%v)", args, err)
+	}
+
+	invalid := []struct {
+		command string
+		args    []string
+	}{
+		{"moves", []string{}},
+		{"moves", []string{"pikachu", "--bogus"}},
+		{"moves", []string{"pikachu", "--method"}},
+		{"pokedex", []string{"--desc=yes"}},
+		{"deposit", []string{"two"}},
+		{"explore", []string{"a", "b"}},
+	}
+	for _, c := range invalid {
+		if _, _, err := commands[c.command].parseArgs(c.args); err == nil {
+			t.Errorf("%s %v: expected an error", c.command, c.args)
+		}
+	}
+}
+
+func TestCommandMetadata(t *testing.T) {
+	for name, cmd := range commands {
+		if cmd.name != name {
+			t.Errorf("%s: registered under the wrong name %s", name, cmd.name)
+		}
+		if !slices.Contains(helpCategories, cmd.category) {
+			t.Errorf("%s: unknown help category %q", name, cmd.category)
+		}
+		for _, a := range cmd.args {
+			if a.description == "" {
+				t.Errorf("%s: argument %s has no description", name, a.name)
+			}
+		}
+		for _, f := range cmd.flags {
+			if f.description == "" {
+				t.Errorf("%s: flag --%s has no description", name, f.name)
+			}
+		}
+	}
+	for _, name := range []string{"info", "moves", "ability", "sprite", "matchup", "search"} {
+		if category := commands[name].category; category != categoryLookup {
+			t.Errorf("%s: expected help category %q, got %q", name, categoryLookup, category)
+		}
+	}
+	if err := commandUsage(&commandConfig{}, "mvoes"); err == nil || !strings.Contains(err.Error(), "moves") {
+		t.Errorf("Expected a suggestion for a misspelled command, got %v", err)
+	}
+}
+
+func TestStructuredOutput(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{}
+	owned = map[int]*ownedPokemon{}
+	var pikachu Pokemon
+	pikachuJSON := `{"id":25,"name":"pikachu","height":4,"weight":60,"base_experience":112,
+		"stats":[{"base_stat":35,"stat":{"name":"hp"}}],"types":[{"slot":1,"type":{"name":"electric"}}]}`
+	if err := json.Unmarshal([]byte(pikachuJSON), &pikachu); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex["pikachu"] = pikachu
+	owned[3] = &ownedPokemon{ID: 3, Species: "pikachu", Level: 10, Nature: "hardy"}
+
+	run := func(config *commandConfig, cmd func(*commandConfig, []string) error, args ...string) string {
+		old := os.Stdout
+		r, w, _ := os.Pipe()
+		os.Stdout = w
+		err := cmd(config, args)
+		w.Close()
+		os.Stdout = old
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		out, _ := io.ReadAll(r)
+		return string(out)
+	}
+
+	config := &commandConfig{flags: flagValues{"output": formatJSON}}
+	var doc inspectDoc
+	if err := json.Unmarshal([]byte(run(config, commandInspect, "3")), &doc); err != nil {
+		t.Fatalf("Expected a JSON document: %v", err)
+	}
+	if doc.Owned == nil || doc.Owned.ID != 3 || len(doc.Stats) != 1 || doc.Stats[0].Actual == nil || doc.Types[0] != "electric" {
+		t.Errorf("Unexpected inspect document: %+v", doc)
+	}
+
+	config = &commandConfig{settings: userSettings{Format: formatYAML}}
+	expected := "---\ncount: 1\nmatches: 1\npokemon:\n- id: 25\n  name: pikachu\n  types:\n  - electric\n"
+	if output := run(config, commandPokedex); output != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, output)
+	}
+
+	var list LocationAreaList
+	if err := json.Unmarshal([]byte(`{"count":2,"next":"https://pokeapi.co/api/v2/location-area/?offset=20","previous":null,
+		"results":[{"name":"canalave-city-area","url":"https://pokeapi.co/api/v2/location-area/1/"}]}`), &list); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	config = &commandConfig{flags: flagValues{"output": formatJSON}}
+	var page map[string]any
+	if err := json.Unmarshal([]byte(run(config, func(c *commandConfig, _ []string) error {
+		return printLocationPage(c, list)
+	})), &page); err != nil {
+		t.Fatalf("Expected a JSON document: %v", err)
+	}
+	if page["previous"] != nil || page["next"] == nil || len(page["results"].([]any)) != 1 {
+		t.Errorf("Unexpected location page: %v", page)
+	}
+}
+
+func TestStructuredStream(t *testing.T) {
+	newGame()
+	config := &commandConfig{startupFormat: formatJSON}
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	for _, line := range []string{"bag", "party", "version", "nosuchcommand", "box list 1", "party --help"} {
+		dispatch(config, line)
+	}
+	w.Close()
+	os.Stdout = old
+	out, _ := io.ReadAll(r)
+
+	decoder := json.NewDecoder(strings.NewReader(string(out)))
+	var docs []map[string]any
+	for decoder.More() {
+		var doc map[string]any
+		if err := decoder.Decode(&doc); err != nil {
+			t.Fatalf("Expected only JSON documents, got %v in:\n%s", err, out)
+		}
+		docs = append(docs, doc)
+	}
+	if len(docs) != 6 {
+		t.Fatalf("Expected a document per line, got %d:\n%s", len(docs), out)
+	}
+	if docs[0]["money"] == nil || docs[1]["party"] == nil || docs[4]["name"] != "box 1" || docs[5]["usage"] == nil {
+		t.Errorf("Unexpected documents: %v", docs)
+	}
+	if docs[2]["command"] != "version" || docs[3]["error"] == nil {
+		t.Errorf("Expected prose as a messages document and an error document, got %v", docs[2:4])
+	}
+}
+
+func TestDocumentZeroValues(t *testing.T) {
+	newGame()
+	var pidgey Pokemon
+	if err := json.Unmarshal([]byte(`{"name":"pidgey","stats":[{"base_stat":40,"stat":{"name":"hp"}}]}`), &pidgey); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pokedex = map[string]Pokemon{"pidgey": pidgey}
+	fainted := &ownedPokemon{ID: 1, Species: "pidgey", Level: 5}
+	fainted.Damage = fainted.computedStats(pidgey)[stats.HP]
+	unknown := &ownedPokemon{ID: 2, Species: "missingno", Level: 5}
+	encounter := encounterDoc{Name: "pidgey"}
+
+	for _, c := range []struct {
+		doc  any
+		want string
+	}{
+		{newMemberDoc(1, fainted), `"hp":0,`},
+		{newMemberDoc(2, unknown), `"hp":null,"max_hp":null,"status":""`},
+		{encounter, `"chance":0,"min_level":0,"max_level":0`},
+	} {
+		data, err := json.Marshal(c.doc)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(string(data), c.want) {
+			t.Errorf("Expected %s in %s", c.want, data)
+		}
+	}
+	pokedex = map[string]Pokemon{}
+}
+
+func TestSortPokedex(t *testing.T) {
+	newGame()
+	newSpecies := func(id int, name string, speed int) Pokemon {
+		var p Pokemon
+		data := fmt.Sprintf(`{"id":%d,"name":%q,"stats":[{"base_stat":%d,"stat":{"name":"speed"}}]}`, id, name, speed)
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return p
+	}
+	list := []Pokemon{newSpecies(25, "pikachu", 90), newSpecies(6, "charizard", 100), newSpecies(143, "snorlax", 30)}
+	names := func() string {
+		var out []string
+		for _, p := range list {
+			out = append(out, p.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	cases := []struct {
+		key      string
+		desc     bool
+		expected string
+	}{
+		{key: "", expected: "charizard,pikachu,snorlax"},
+		{key: "name", desc: true, expected: "snorlax,pikachu,charizard"},
+		{key: "speed", expected: "snorlax,pikachu,charizard"},
+		{key: "total", desc: true, expected: "charizard,pikachu,snorlax"},
+	}
+	for _, c := range cases {
+		if err := sortPokedex(list, c.key, c.desc); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if actual := names(); actual != c.expected {
+			t.Errorf("sort %q desc=%v: expected %s, got %s", c.key, c.desc, c.expected, actual)
+		}
+	}
+	if err := sortPokedex(list, "colour", false); err == nil {
+		t.Errorf("Expected an error for an unknown sort key")
+	}
+}
+
+func TestCommandSprite(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/mew", []byte(`{"name":"mew","sprites":{
+		"front_default":"https://example.com/mew.png",
+		"back_shiny":"https://example.com/mew-back-shiny.png",
+		"versions":{"generation-i":{"red-blue":{"front_default":"https://example.com/mew-rb.png"}}}}}`))
+	png, err := os.ReadFile("internal/sprite/testdata/blue.png")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cache.Add("https://example.com/mew-rb.png", png)
+
+	p, err := getPokemon("mew", cache)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	cases := []struct {
+		version     string
+		back, shiny bool
+		expected    string
+	}{
+		{expected: "https://example.com/mew.png"},
+		{back: true, shiny: true, expected: "https://example.com/mew-back-shiny.png"},
+		{version: "red-blue", expected: "https://example.com/mew-rb.png"},
+	}
+	for _, c := range cases {
+		if url, err := spriteURL(p, c.version, c.back, c.shiny); err != nil || url != c.expected {
+			t.Errorf("%+v: expected %s, got %s (%v)", c, c.expected, url, err)
+		}
+	}
+	if _, err := spriteURL(p, "red-blue", false, true); err == nil {
+		t.Errorf("Expected an error for a missing shiny red-blue sprite")
+	}
+	if _, err := spriteURL(p, "stadium", false, false); err == nil {
+		t.Errorf("Expected an error for an unknown version")
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err = commandSprite(&commandConfig{cache: cache, flags: flagValues{"version": "red-blue"}}, []string{"mew"})
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	if string(out) != "%%%%\n%%%%\n" {
+		t.Errorf("Expected ASCII art without color, got %q", out)
+	}
+}
+
+func TestLocalizedOutput(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/location-area/canalave-city-area", []byte(`{"name":"canalave-city-area",
+		"names":[{"name":"Canalave City","language":{"name":"en"}},{"name":"Joliberges","language":{"name":"fr"}}],
+		"pokemon_encounters":[{"pokemon":{"name":"tentacool"}},{"pokemon":{"name":"staryu"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/tentacool", []byte(`{"name":"tentacool",
+		"names":[{"name":"Tentacool","language":{"name":"fr"}}]}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon-species/staryu", []byte(`{"name":"staryu",
+		"names":[{"name":"Stari","language":{"name":"fr"}}]}`))
+
+	path := t.TempDir() + "/config.json"
+	config := &commandConfig{cache: cache, configPath: path}
+	if err := commandLanguage(config, []string{"FR"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := commandLanguage(config, []string{"klingon"}); err == nil {
+		t.Errorf("Expected an error for an unknown language")
+	}
+	if saved, _ := loadSettings(path); saved.Language != "fr" {
+		t.Errorf("Expected the language to be saved, got %q", saved.Language)
+	}
+
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err := commandExplore(config, []string{"canalave-city-area"})
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	expected := "Exploration de Joliberges (canalave-city-area)...\nPokémon trouvés :\n - tentacool\n - Stari (staryu)\n"
+	if string(out) != expected {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expected, out)
+	}
+
+	var species PokemonSpecies
+	if err := json.Unmarshal([]byte(`{"flavor_text_entries":[
+		{"flavor_text":"It stores\nelectricity.","language":{"name":"en"}},
+		{"flavor_text":"Il stocke\fl'électricité.","language":{"name":"fr"}}],
+		"genera":[{"genus":"Mouse Pokémon","language":{"name":"en"}}]}`), &species); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if text := flavorText(species, "fr"); text != "Il stocke l'électricité." {
+		t.Errorf("Expected French flavor text, got %q", text)
+	}
+	if text := flavorText(species, "de"); text != "It stores electricity." {
+		t.Errorf("Expected English fallback, got %q", text)
+	}
+	if g := genus(species, "ja"); g != "Mouse Pokémon" {
+		t.Errorf("Expected English genus fallback, got %q", g)
+	}
+}
+
+func TestVersionFilter(t *testing.T) {
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/version/platinum", []byte(`{"name":"platinum","version_group":{"name":"platinum"}}`))
+	config := &commandConfig{cache: cache}
+	if err := commandVersion(config, []string{"platinum"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.version != "platinum" || config.versionGroup != "platinum" {
+		t.Errorf("Expected platinum to be selected, got %s/%s", config.version, config.versionGroup)
+	}
+
+	var area LocationDetails
+	if err := json.Unmarshal([]byte(`{"pokemon_encounters":[
+		{"pokemon":{"name":"tentacool"},"version_details":[
+			{"version":{"name":"diamond"},"max_chance":60,"encounter_details":[{"min_level":20,"max_level":30}]},
+			{"version":{"name":"platinum"},"max_chance":45,"encounter_details":[{"min_level":20,"max_level":25},{"min_level":15,"max_level":40}]}]},
+		{"pokemon":{"name":"staryu"},"version_details":[
+			{"version":{"name":"diamond"},"max_chance":5,"encounter_details":[{"min_level":20,"max_level":30}]}]}]}`), &area); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	encounters := config.areaEncounters(&area)
+	if len(encounters) != 1 || encounters[0] != (areaEncounter{Name: "tentacool", Chance: 45, MinLevel: 15, MaxLevel: 40}) {
+		t.Errorf("Expected only platinum tentacool, got %+v", encounters)
+	}
+
+	var p Pokemon
+	if err := json.Unmarshal([]byte(`{"held_items":[
+		{"item":{"name":"dragon-scale"},"version_details":[{"rarity":5,"version":{"name":"diamond"}}]},
+		{"item":{"name":"poison-barb"},"version_details":[{"rarity":5,"version":{"name":"diamond"}},{"rarity":50,"version":{"name":"platinum"}}]}]}`), &p); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if items := config.heldItems(p); len(items) != 1 || items[0] != (heldItem{Name: "poison-barb", Rarity: 50}) {
+		t.Errorf("Expected platinum held items only, got %+v", items)
+	}
+
+	if err := commandVersion(config, []string{"all"}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(config.areaEncounters(&area)) != 2 || len(config.heldItems(p)) != 2 {
+		t.Errorf("Expected every version after clearing the filter")
+	}
+}
+
+func TestEncounterMethods(t *testing.T) {
+	var area LocationDetails
+	if err := json.Unmarshal([]byte(`{
+		"encounter_method_rates":[
+			{"encounter_method":{"name":"walk"},"version_details":[{"rate":10,"version":{"name":"diamond"}},{"rate":20,"version":{"name":"pearl"}}]},
+			{"encounter_method":{"name":"surf"},"version_details":[{"rate":5,"version":{"name":"diamond"}}]}],
+		"pokemon_encounters":[
+			{"pokemon":{"name":"bidoof"},"version_details":[
+				{"version":{"name":"diamond"},"encounter_details":[
+					{"method":{"name":"walk"},"chance":20,"min_level":3,"max_level":3,"condition_values":[{"name":"time-morning"}]},
+					{"method":{"name":"walk"},"chance":10,"min_level":2,"max_level":4,"condition_values":[{"name":"time-day"}]}]},
+				{"version":{"name":"pearl"},"encounter_details":[
+					{"method":{"name":"walk"},"chance":40,"min_level":5,"max_level":6}]}]},
+			{"pokemon":{"name":"starly"},"version_details":[
+				{"version":{"name":"diamond"},"encounter_details":[{"method":{"name":"walk"},"chance":10,"min_level":2,"max_level":2}]}]},
+			{"pokemon":{"name":"tentacool"},"version_details":[
+				{"version":{"name":"diamond"},"encounter_details":[{"method":{"name":"surf"},"chance":90,"min_level":20,"max_level":30}]}]}]}`), &area); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	config := &commandConfig{}
+	methods := config.encounterMethods(&area)
+	if len(methods) != 2 || methods[0].Method != "walk" || methods[0].Rate != 20 || methods[1].Method != "surf" {
+		t.Fatalf("Expected walk then surf, got %+v", methods)
+	}
+	bidoof := methods[0].Pokemon[0]
+	if bidoof.Chance != 40 || bidoof.MinLevel != 2 || bidoof.MaxLevel != 6 || strings.Join(bidoof.Conditions, ",") != "time-morning,time-day" {
+		t.Errorf("Unexpected merged bidoof encounter: %+v", bidoof)
+	}
+
+	config.version = "diamond"
+	methods = config.encounterMethods(&area)
+	if bidoof := methods[0].Pokemon[0]; bidoof.Chance != 30 || bidoof.MaxLevel != 4 || methods[0].Rate != 10 {
+		t.Errorf("Expected diamond chances summed to 30, got %+v", methods[0])
+	}
+	if err := sortEncounters(methods[0].Pokemon, "rarity"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if methods[0].Pokemon[0].Name != "starly" {
+		t.Errorf("Expected the rarest pokemon first, got %+v", methods[0].Pokemon)
+	}
+	if err := sortEncounters(methods[0].Pokemon, "weight"); err == nil {
+		t.Errorf("Expected an error for an unknown sort key")
+	}
+}
+
+func TestRegionNavigation(t *testing.T) {
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/region/sinnoh", []byte(`{"name":"sinnoh",
+		"locations":[{"name":"eterna-forest"},{"name":"canalave-city"}]}`))
+	cache.Add("https://pokeapi.co/api/v2/location/eterna-forest", []byte(`{"name":"eterna-forest",
+		"region":{"name":"sinnoh"},"areas":[{"name":"eterna-forest-area"}]}`))
+
+	config := &commandConfig{cache: cache}
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	errs := []error{
+		commandLocations(config, []string{"sinnoh"}),
+		commandAreas(config, []string{"eterna-forest"}),
+	}
+	w.Close()
+	os.Stdout = old
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	for _, want := range []string{" - canalave-city\n", "eterna-forest (sinnoh):\n - eterna-forest-area\n"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got %s", want, output)
+		}
+	}
+
+	if err := commandLocations(config, nil); err == nil {
+		t.Errorf("Expected an error for a missing region")
+	}
+}
+
+func TestWhere(t *testing.T) {
+	newGame()
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokemon/pikachu", []byte(`{"name":"pikachu",
+		"location_area_encounters":"https://pokeapi.co/api/v2/pokemon/25/encounters"}`))
+	cache.Add("https://pokeapi.co/api/v2/pokemon/25/encounters", []byte(`[
+		{"location_area":{"name":"trophy-garden-area"},"version_details":[
+			{"version":{"name":"diamond"},"encounter_details":[
+				{"method":{"name":"walk"},"chance":10,"min_level":14,"max_level":14},
+				{"method":{"name":"walk"},"chance":10,"min_level":16,"max_level":16}]},
+			{"version":{"name":"pearl"},"encounter_details":[{"method":{"name":"walk"},"chance":5,"min_level":15,"max_level":17}]}]},
+		{"location_area":{"name":"viridian-forest-area"},"version_details":[
+			{"version":{"name":"red"},"encounter_details":[{"method":{"name":"walk"},"chance":5,"min_level":3,"max_level":5}]}]}]`))
+	cache.Add("https://pokeapi.co/api/v2/location-area/trophy-garden-area", []byte(`{"name":"trophy-garden-area",
+		"pokemon_encounters":[{"pokemon":{"name":"pikachu"}}]}`))
+
+	config := &commandConfig{cache: cache}
+	p, _ := getPokemon("pikachu", cache)
+	list, err := config.habitats(p, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := []habitat{
+		{Area: "trophy-garden-area", Method: "walk", Versions: []string{"diamond", "pearl"}, MinLevel: 14, MaxLevel: 17, Chance: 20},
+		{Area: "viridian-forest-area", Method: "walk", Versions: []string{"red"}, MinLevel: 3, MaxLevel: 5, Chance: 5},
+	}
+	if !reflect.DeepEqual(list, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, list)
+	}
+
+	config.flags = flagValues{"version": "red"}
+	if _, list, err := config.findHabitats("pikachu"); err != nil || len(list) != 1 || list[0].Area != "viridian-forest-area" {
+		t.Errorf("Expected only the red area, got %+v, %v", list, err)
+	}
+	config.flags = flagValues{"version": "platinum"}
+	if _, _, err := config.findHabitats("pikachu"); err == nil {
+		t.Errorf("Expected an error when the Pokemon is not in the version")
+	}
+
+	config.flags = nil
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	errs := []error{commandWhere(config, []string{"pikachu"}), commandGoto(config, []string{"pikachu"})}
+	w.Close()
+	os.Stdout = old
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	out, _ := io.ReadAll(r)
+	if !regexp.MustCompile(`trophy-garden-area\s+walk\s+14-17\s+20%\s+diamond, pearl`).Match(out) {
+		t.Errorf("Expected the trophy garden row, got %s", out)
+	}
+	if !strings.Contains(string(out), "Use goto pikachu to explore trophy-garden-area.") {
+		t.Errorf("Expected a goto hint, got %s", out)
+	}
+	if config.area == nil || config.area.Name != "trophy-garden-area" {
+		t.Errorf("Expected goto to explore the best area")
+	}
+}
+
+func TestCommandDex(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{"bulbasaur": {Name: "bulbasaur"}, "pikachu": {Name: "pikachu"}}
+	seen = map[string]bool{"charmander": true}
+	cache := pokecache.NewCache(5 * time.Minute)
+	cache.Add("https://pokeapi.co/api/v2/pokedex/kanto", []byte(`{"name":"kanto","region":{"name":"kanto"},
+		"names":[{"name":"Kanto","language":{"name":"en"}}],
+		"pokemon_entries":[
+			{"entry_number":4,"pokemon_species":{"name":"charmander"}},
+			{"entry_number":1,"pokemon_species":{"name":"bulbasaur"}},
+			{"entry_number":7,"pokemon_species":{"name":"squirtle"}},
+			{"entry_number":25,"pokemon_species":{"name":"pikachu"}}]}`))
+
+	dex, err := getPokedex("kanto", cache)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	doc := dexProgress(dex)
+	if doc.Total != 4 || doc.Seen != 3 || doc.Caught != 2 || doc.Percent != 50 {
+		t.Errorf("Expected 3 seen and 2 caught of 4, got %+v", doc)
+	}
+	expected := []dexEntryDoc{{Number: 4, Name: "charmander", Seen: true}, {Number: 7, Name: "squirtle"}}
+	if !reflect.DeepEqual(doc.Missing, expected) {
+		t.Errorf("Expected %+v missing, got %+v", expected, doc.Missing)
+	}
+
+	config := &commandConfig{cache: cache}
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	err = commandDex(config, []string{"kanto"})
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	out, _ := io.ReadAll(r)
+	if !regexp.MustCompile(`Caught: 2/4 \(50\.0%\)\nMissing:\n[^\n]*\n004\s+charmander\s+seen\n007\s+squirtle`).Match(out) {
+		t.Errorf("Expected completion and missing entries, got %s", out)
+	}
+
+	cache.Add("https://pokeapi.co/api/v2/generation/1", []byte(`{"id":1,"name":"generation-i","main_region":{"name":"kanto"},
+		"pokemon_species":[
+			{"name":"squirtle","url":"https://pokeapi.co/api/v2/pokemon-species/7/"},
+			{"name":"bulbasaur","url":"https://pokeapi.co/api/v2/pokemon-species/1/"},
+			{"name":"charmander","url":"https://pokeapi.co/api/v2/pokemon-species/4/"}]}`))
+	config.flags = flagValues{"generation": "1", "output": formatJSON}
+	r, w, _ = os.Pipe()
+	os.Stdout = w
+	err = commandDex(config, nil)
+	w.Close()
+	os.Stdout = old
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var generation dexDoc
+	if err := json.NewDecoder(r).Decode(&generation); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected = []dexEntryDoc{{Number: 4, Name: "charmander", Seen: true}, {Number: 7, Name: "squirtle"}}
+	if generation.Pokedex != "generation-i" || generation.Region != "kanto" || generation.Caught != 1 || !reflect.DeepEqual(generation.Missing, expected) {
+		t.Errorf("Expected generation i with charmander and squirtle missing, got %+v", generation)
+	}
+}
+
+func TestPokedexQuery(t *testing.T) {
+	newGame()
+	newSpecies := func(data string) Pokemon {
+		var p Pokemon
+		if err := json.Unmarshal([]byte(data), &p); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return p
+	}
+	pokedex = map[string]Pokemon{
+		"charizard": newSpecies(`{"id":6,"name":"charizard","base_experience":240,"weight":905,
+			"types":[{"type":{"name":"fire"}},{"type":{"name":"flying"}}],"abilities":[{"ability":{"name":"blaze"}}]}`),
+		"vulpix": newSpecies(`{"id":37,"name":"vulpix","base_experience":60,"weight":99,
+			"types":[{"type":{"name":"fire"}}],"abilities":[{"ability":{"name":"flash-fire"}}]}`),
+		"gengar": newSpecies(`{"id":94,"name":"gengar","base_experience":250,"weight":405,
+			"types":[{"type":{"name":"ghost"}},{"type":{"name":"poison"}}],"abilities":[{"ability":{"name":"levitate"}}]}`),
+		"weezing": newSpecies(`{"id":110,"name":"weezing","base_experience":172,"weight":95,
+			"types":[{"type":{"name":"poison"}}],"abilities":[{"ability":{"name":"levitate"}}]}`),
+	}
+	names := func(list []Pokemon) string {
+		var out []string
+		for _, p := range list {
+			out = append(out, p.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	cases := []struct {
+		query    string
+		expected string
+	}{
+		{query: "base_experience>200 and has_ability:levitate", expected: "gengar"},
+		{query: "weight <= 99", expected: "vulpix,weezing"},
+		{query: "has_type:fire or name=weezing", expected: "charizard,vulpix,weezing"},
+		{query: "has_type:poison and weight>100 or id=37", expected: "vulpix,gengar"},
+		{query: "name!=charizard and base_experience>=172", expected: "gengar,weezing"},
+	}
+	for _, c := range cases {
+		filter, err := parseQuery(c.query)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", c.query, err)
+		}
+		if actual := names(filterPokedex(sortedPokedex(), filter)); actual != c.expected {
+			t.Errorf("%q: expected %s, got %s", c.query, c.expected, actual)
+		}
+	}
+	for _, query := range []string{"", "weight", "colour>3", "weight>heavy", "has_color:red", "name>b", "weight>1 and"} {
+		if _, err := parseQuery(query); err == nil {
+			t.Errorf("%q: expected an error", query)
+		}
+	}
+
+	config := &commandConfig{flags: flagValues{"type": "fire", "sort": "weight", "desc": "true", "output": formatJSON}}
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	errs := []error{commandPokedex(config, nil)}
+	config.flags = flagValues{"limit": "1", "page": "2", "sort": "name"}
+	errs = append(errs, commandPokedex(config, nil))
+	w.Close()
+	os.Stdout = old
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	if !regexp.MustCompile(`"matches": 2,\s+"pokemon": \[\s+\{\s+"id": 6`).MatchString(output) {
+		t.Errorf("Expected the heaviest fire type first, got %s", output)
+	}
+	if !strings.Contains(output, "gengar") || !strings.Contains(output, "Showing 2-2 of 4. Use --limit 1 --page 3 for more.") {
+		t.Errorf("Expected the second page, got %s", output)
+	}
+
+	config.flags = flagValues{"page": "9"}
+	if err := commandPokedex(config, nil); err == nil {
+		t.Errorf("Expected an error for a page past the end")
+	}
+}
+
+func TestPokedexNoMatches(t *testing.T) {
+	newGame()
+	pokedex = map[string]Pokemon{"pikachu": {ID: 25, Name: "pikachu"}}
+	config := &commandConfig{}
+	old := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+	config.flags = flagValues{"type": "water"}
+	errs := []error{commandPokedex(config, nil)}
+	pokedex = map[string]Pokemon{}
+	config.flags = nil
+	errs = append(errs, commandPokedex(config, nil))
+	config.flags = flagValues{"output": formatJSON}
+	errs = append(errs, commandPokedex(config, nil))
+	w.Close()
+	os.Stdout = old
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	out, _ := io.ReadAll(r)
+	output := string(out)
+	for _, want := range []string{"No caught Pokemon match.", "You have not caught any Pokemon yet.", `"count": 0`} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in output, got %s", want, output)
+		}
+	}
+}
+
+func TestRollCatch(t *testing.T) {
+	rate := func(baseExperience int) int {
+		rng := rand.New(rand.NewSource(1))
+		caught := 0
+		for i := 0; i < 1000; i++ {
+			if rollCatch(rng, baseExperience, 1) {
+				caught++
+			}
+		}
+		return caught
 	}
-	if !strings.Contains(output2, "Types:") {
-		t.Errorf("Expected 'Types:', got %s", output2)
+	weak, strong := rate(50), rate(600)
+	if weak <= strong {
+		t.Errorf("Expected weak species to be caught more often than strong ones, got %d and %d of 1000", weak, strong)
 	}
-	if !strings.Contains(output2, "-electric") {
-		t.Errorf("Expected '-electric', got %s", output2)
+	if weak < 800 || strong > 200 {
+		t.Errorf("Expected roughly 90%% and 10%% catch rates, got %d and %d of 1000", weak, strong)
 	}
 }
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/pokecache"
+)
+
+type saveData struct {
+	// Pokedex holds the caught species names, which are fetched again on
+	// load. Older saves hold the full Pokemon data instead.
+	Pokedex     json.RawMessage `json:"pokedex,omitempty"`
+	Seen        []string        `json:"seen"`
+	Owned       []*ownedPokemon `json:"owned"`
+	NextOwnedID int             `json:"next_owned_id"`
+	Party       []int           `json:"party"`
+	Boxes       []*pcBox        `json:"boxes"`
+	Bag         []*bagItem      `json:"bag"`
+	Money       int             `json:"money"`
+}
+
+// unloadedSpecies are caught species whose data couldn't be fetched when the
+// save was loaded. They are written back so the next load can try again.
+var unloadedSpecies []string
+
+func defaultSavePath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "pokedexcli", "save.json")
+}
+
+// loadGame restores the collection from path, fetching the caught species
+// through the cache. A missing save file just means a new game.
+func loadGame(path string, cache *pokecache.Cache) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var save saveData
+	if err := json.Unmarshal(data, &save); err != nil {
+		return err
+	}
+	var fetchErr error
+	if len(save.Pokedex) > 0 {
+		fetchErr = loadPokedex(save.Pokedex, cache)
+	}
+	seen = make(map[string]bool)
+	for _, name := range save.Seen {
+		seen[name] = true
+	}
+	owned = make(map[int]*ownedPokemon)
+	for _, p := range save.Owned {
+		owned[p.ID] = p
+	}
+	if save.NextOwnedID > 0 {
+		nextOwnedID = save.NextOwnedID
+	}
+	// Ids that no longer match an owned Pokemon, from an edited or damaged
+	// save, are dropped rather than left to crash later lookups.
+	party = nil
+	for _, id := range save.Party {
+		if _, ok := owned[id]; ok {
+			party = append(party, id)
+		}
+	}
+	if len(save.Boxes) > 0 {
+		boxes = save.Boxes
+		for _, box := range boxes {
+			for i, id := range box.Slots {
+				if _, ok := owned[id]; !ok {
+					box.Slots[i] = 0
+				}
+			}
+		}
+	}
+	// Saves from before the bag existed keep the starter bag and money.
+	if save.Bag != nil {
+		bag = make(map[string]*bagItem)
+		for _, item := range save.Bag {
+			bag[item.Name] = item
+		}
+		money = save.Money
+	}
+	return fetchErr
+}
+
+func loadPokedex(data json.RawMessage, cache *pokecache.Cache) error {
+	unloadedSpecies = nil
+	var full map[string]Pokemon
+	if err := json.Unmarshal(data, &full); err == nil {
+		pokedex = full
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return err
+	}
+	pokedex = make(map[string]Pokemon)
+	for _, name := range names {
+		p, err := getPokemon(name, cache)
+		if err != nil {
+			unloadedSpecies = append(unloadedSpecies, name)
+			continue
+		}
+		pokedex[name] = p
+	}
+	if len(unloadedSpecies) > 0 {
+		return fmt.Errorf("could not fetch %s", strings.Join(unloadedSpecies, ", "))
+	}
+	return nil
+}
+
+// savedPokedex lists the Pokemon to save: everything in the Pokedex and
+// anything that failed to load.
+func savedPokedex() []string {
+	names := append([]string{}, unloadedSpecies...)
+	for name := range pokedex {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func saveGame(path string) error {
+	if path == "" {
+		return nil
+	}
+	species, err := json.Marshal(savedPokedex())
+	if err != nil {
+		return err
+	}
+	save := saveData{
+		Pokedex:     species,
+		Seen:        sortedSeen(),
+		Owned:       sortedOwned(),
+		NextOwnedID: nextOwnedID,
+		Party:       party,
+		Boxes:       boxes,
+		Bag:         sortedBag(),
+		Money:       money,
+	}
+	data, err := json.Marshal(save)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
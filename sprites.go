@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/KindMinotaur/pokedexcli/internal/sprite"
+)
+
+// spriteURL picks the sprite for a side and coloring, from a game version
+// when one is given and from the default sprites otherwise.
+func spriteURL(p Pokemon, version string, back, shiny bool) (string, error) {
+	key := "front_"
+	if back {
+		key = "back_"
+	}
+	if shiny {
+		key += "shiny"
+	} else {
+		key += "default"
+	}
+	description := fmt.Sprintf("%s %s", p.Name, key)
+
+	var urls map[string]any
+	if version == "" {
+		data, err := json.Marshal(p.Sprites)
+		if err != nil {
+			return "", err
+		}
+		if err := json.Unmarshal(data, &urls); err != nil {
+			return "", err
+		}
+	} else {
+		data, err := json.Marshal(p.Sprites.Versions)
+		if err != nil {
+			return "", err
+		}
+		var generations map[string]map[string]map[string]any
+		if err := json.Unmarshal(data, &generations); err != nil {
+			return "", err
+		}
+		var versions []string
+		for _, games := range generations {
+			for name, sprites := range games {
+				versions = append(versions, name)
+				if name == version {
+					urls = sprites
+				}
+			}
+		}
+		if urls == nil {
+			sort.Strings(versions)
+			return "", fmt.Errorf("unknown sprite version: %s (use one of %v)", version, versions)
+		}
+		description += " in " + version
+	}
+	url, _ := urls[key].(string)
+	if url == "" {
+		return "", fmt.Errorf("there is no %s sprite", description)
+	}
+	return url, nil
+}
+
+func spriteMode(config *commandConfig) sprite.Mode {
+	switch {
+	case config.style.TrueColor:
+		return sprite.TrueColor
+	case config.style.Color:
+		return sprite.Color256
+	}
+	return sprite.ASCII
+}
+
+func commandSprite(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("sprite command requires a pokemon name")
+	}
+	p, err := getPokemon(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(pokemonNames, args[0], err)
+	}
+	back, shiny := config.flags.has("back"), config.flags.has("shiny")
+	url, err := spriteURL(p, config.flags["version"], back, shiny)
+	if err != nil {
+		return err
+	}
+	if _, explicit := config.flags["version"]; !explicit {
+		// Use the session version's sprite when the game has one.
+		for _, version := range []string{config.version, config.versionGroup} {
+			if version == "" {
+				continue
+			}
+			if versionURL, err := spriteURL(p, version, back, shiny); err == nil {
+				url = versionURL
+				break
+			}
+		}
+	}
+	if config.structuredOutput() {
+		return config.emit(namedResource{Name: p.Name, URL: url})
+	}
+	img, err := fetchImage(url, config.cache)
+	if err != nil {
+		return err
+	}
+	width := config.style.Width
+	if width == 0 {
+		width = 80
+	}
+	fmt.Print(sprite.Render(img, sprite.Options{Mode: spriteMode(config), Width: width}))
+	return nil
+}
@@ -0,0 +1,251 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/KindMinotaur/pokedexcli/internal/stats"
+)
+
+const (
+	partySize    = 6
+	boxSize      = 30
+	initialBoxes = 8
+)
+
+type pcBox struct {
+	Name  string       `json:"name"`
+	Slots [boxSize]int `json:"slots"`
+}
+
+var party []int
+var boxes []*pcBox
+
+func newBoxes() []*pcBox {
+	list := make([]*pcBox, 0, initialBoxes)
+	for i := 0; i < initialBoxes; i++ {
+		list = append(list, &pcBox{Name: fmt.Sprintf("box %d", i+1)})
+	}
+	return list
+}
+
+// storeOwned places a newly registered Pokemon in the party when there is
+// room and otherwise in the first free PC slot, opening a new box if needed.
+func storeOwned(p *ownedPokemon) string {
+	if len(party) < partySize {
+		party = append(party, p.ID)
+		return "party"
+	}
+	return storeInBox(p)
+}
+
+func storeInBox(p *ownedPokemon) string {
+	for _, box := range boxes {
+		for i, id := range box.Slots {
+			if id == 0 {
+				box.Slots[i] = p.ID
+				return box.Name
+			}
+		}
+	}
+	box := &pcBox{Name: fmt.Sprintf("box %d", len(boxes)+1)}
+	box.Slots[0] = p.ID
+	boxes = append(boxes, box)
+	return box.Name
+}
+
+func partyIndex(id int) int {
+	for i, partyID := range party {
+		if partyID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func boxLocation(id int) (*pcBox, int) {
+	for _, box := range boxes {
+		for i, slotID := range box.Slots {
+			if slotID == id {
+				return box, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+func parseOwnedID(arg string) (int, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return 0, fmt.Errorf("%s is not a pokemon id", arg)
+	}
+	if _, ok := owned[id]; !ok {
+		return 0, fmt.Errorf("you don't own a pokemon with id %d", id)
+	}
+	return id, nil
+}
+
+func describeOwned(p *ownedPokemon) string {
+	desc := fmt.Sprintf("#%d %s Lv%d", p.ID, p.Species, p.Level)
+	if species, ok := pokedex[p.Species]; ok {
+		maxHP := p.computedStats(species)[stats.HP]
+		desc += fmt.Sprintf(" %d/%d HP", maxHP-p.Damage, maxHP)
+	}
+	if p.Status != "" {
+		desc += fmt.Sprintf(" [%s]", p.Status)
+	}
+	return desc
+}
+
+func commandParty(config *commandConfig, args []string) error {
+	if len(args) > 0 {
+		if args[0] != "swap" || len(args) != 3 {
+			return fmt.Errorf("usage: party [swap <slot> <slot>]")
+		}
+		if config.battle != nil {
+			return fmt.Errorf("you can't reorder your party during a battle")
+		}
+		a, errA := strconv.Atoi(args[1])
+		b, errB := strconv.Atoi(args[2])
+		if errA != nil || errB != nil || a < 1 || b < 1 || a > len(party) || b > len(party) {
+			return fmt.Errorf("party slots must be between 1 and %d", len(party))
+		}
+		party[a-1], party[b-1] = party[b-1], party[a-1]
+	}
+	if config.structuredOutput() {
+		members := []memberDoc{}
+		for i, id := range party {
+			members = append(members, newMemberDoc(i+1, owned[id]))
+		}
+		return config.emit(struct {
+			Party []memberDoc `json:"party"`
+		}{members})
+	}
+	if len(party) == 0 {
+		fmt.Println(config.t("party.empty"))
+		return nil
+	}
+	fmt.Println(config.t("party.title"))
+	for i, id := range party {
+		fmt.Printf("  %d. %s\n", i+1, describeOwned(owned[id]))
+	}
+	return nil
+}
+
+func commandDeposit(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("deposit command requires a pokemon id")
+	}
+	id, err := parseOwnedID(args[0])
+	if err != nil {
+		return err
+	}
+	i := partyIndex(id)
+	if i < 0 {
+		return fmt.Errorf("pokemon %d is not in your party", id)
+	}
+	if len(party) == 1 {
+		return fmt.Errorf("you can't deposit your last party pokemon")
+	}
+	if config.battle != nil && config.battle.lead.ID == id {
+		return fmt.Errorf("you can't deposit a pokemon that is battling")
+	}
+	party = append(party[:i], party[i+1:]...)
+	boxName := storeInBox(owned[id])
+	fmt.Println(config.t("storage.deposited", owned[id].Species, boxName))
+	return nil
+}
+
+func commandWithdraw(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("withdraw command requires a pokemon id")
+	}
+	id, err := parseOwnedID(args[0])
+	if err != nil {
+		return err
+	}
+	box, slot := boxLocation(id)
+	if box == nil {
+		return fmt.Errorf("pokemon %d is not in the PC", id)
+	}
+	if len(party) >= partySize {
+		return fmt.Errorf("your party is full, deposit a pokemon first")
+	}
+	box.Slots[slot] = 0
+	party = append(party, id)
+	fmt.Println(config.t("storage.withdrawn", owned[id].Species, box.Name))
+	return nil
+}
+
+func commandBox(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: box list [number] | box rename <number> <name>")
+	}
+	switch args[0] {
+	case "list":
+		if len(args) == 1 {
+			docs := []boxDoc{}
+			for i, box := range boxes {
+				count := 0
+				for _, id := range box.Slots {
+					if id != 0 {
+						count++
+					}
+				}
+				docs = append(docs, boxDoc{Number: i + 1, Name: box.Name, Count: count})
+			}
+			if config.structuredOutput() {
+				return config.emit(struct {
+					Boxes []boxDoc `json:"boxes"`
+				}{docs})
+			}
+			for _, doc := range docs {
+				fmt.Printf("%d. %s (%d/%d)\n", doc.Number, doc.Name, doc.Count, boxSize)
+			}
+			return nil
+		}
+		box, err := boxByNumber(args[1])
+		if err != nil {
+			return err
+		}
+		if config.structuredOutput() {
+			number, _ := strconv.Atoi(args[1])
+			doc := boxDoc{Number: number, Name: box.Name, Pokemon: []memberDoc{}}
+			for i, id := range box.Slots {
+				if id != 0 {
+					doc.Count++
+					doc.Pokemon = append(doc.Pokemon, newMemberDoc(i+1, owned[id]))
+				}
+			}
+			return config.emit(doc)
+		}
+		fmt.Printf("%s:\n", box.Name)
+		for i, id := range box.Slots {
+			if id != 0 {
+				fmt.Printf("  %d. %s\n", i+1, describeOwned(owned[id]))
+			}
+		}
+		return nil
+	case "rename":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: box rename <number> <name>")
+		}
+		box, err := boxByNumber(args[1])
+		if err != nil {
+			return err
+		}
+		box.Name = strings.Join(args[2:], " ")
+		fmt.Println(config.t("box.renamed", args[1], box.Name))
+		return nil
+	}
+	return fmt.Errorf("unknown box subcommand: %s", args[0])
+}
+
+func boxByNumber(arg string) (*pcBox, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 || n > len(boxes) {
+		return nil, fmt.Errorf("box number must be between 1 and %d", len(boxes))
+	}
+	return boxes[n-1], nil
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+type areaEncounter struct {
+	Name     string
+	URL      string
+	Chance   int
+	MinLevel int
+	MaxLevel int
+}
+
+// areaEncounters lists the Pokemon found in an area. With a version set
+// only those found in that version are listed, with that version's chance
+// and levels; otherwise chance and levels span every version.
+func (config *commandConfig) areaEncounters(area *LocationDetails) []areaEncounter {
+	var encounters []areaEncounter
+	for _, e := range area.PokemonEncounters {
+		encounter := areaEncounter{Name: e.Pokemon.Name, URL: e.Pokemon.URL}
+		found := false
+		for _, vd := range e.VersionDetails {
+			if config.version != "" && vd.Version.Name != config.version {
+				continue
+			}
+			found = true
+			encounter.Chance = max(encounter.Chance, vd.MaxChance)
+			for _, d := range vd.EncounterDetails {
+				if encounter.MinLevel == 0 || d.MinLevel < encounter.MinLevel {
+					encounter.MinLevel = d.MinLevel
+				}
+				encounter.MaxLevel = max(encounter.MaxLevel, d.MaxLevel)
+			}
+		}
+		if found || config.version == "" {
+			encounters = append(encounters, encounter)
+		}
+	}
+	return encounters
+}
+
+// rollLevel picks a level in the encounter's range, or between 5 and 30
+// when the range is unknown.
+func (e areaEncounter) rollLevel(rng *rand.Rand) int {
+	if e.MaxLevel == 0 {
+		return 5 + rng.Intn(26)
+	}
+	return e.MinLevel + rng.Intn(e.MaxLevel-e.MinLevel+1)
+}
+
+type heldItem struct {
+	Name   string `json:"name"`
+	Rarity int    `json:"rarity"`
+}
+
+// heldItems lists the items a wild Pokemon may hold and the chance in
+// percent, for the current version or the first version listed when none
+// is set.
+func (config *commandConfig) heldItems(p Pokemon) []heldItem {
+	var items []heldItem
+	for _, item := range p.HeldItems {
+		for _, d := range item.VersionDetails {
+			if config.version == "" || d.Version.Name == config.version {
+				items = append(items, heldItem{Name: item.Item.Name, Rarity: d.Rarity})
+				break
+			}
+		}
+	}
+	return items
+}
+
+func commandVersion(config *commandConfig, args []string) error {
+	if len(args) == 0 {
+		if config.version == "" {
+			fmt.Println(config.t("version.all"))
+		} else {
+			fmt.Println(config.t("version.current", config.version, config.versionGroup))
+		}
+		return nil
+	}
+	if args[0] == "all" {
+		config.version, config.versionGroup = "", ""
+		fmt.Println(config.t("version.all"))
+		return nil
+	}
+	version, err := getVersion(args[0], config.cache)
+	if err != nil {
+		return config.withSuggestions(versionNames, args[0], err)
+	}
+	config.version = version.Name
+	config.versionGroup = version.VersionGroup.Name
+	fmt.Println(config.t("version.set", config.version, config.versionGroup))
+	return nil
+}